@@ -0,0 +1,98 @@
+// Command analyze prints the metrics of levels: the number of colors, the
+// distance between the dots and the length of the path of each color, the
+// number of the moves forced from the start, the branching factor of the
+// search and the number of solutions. The metrics are printed as text, or
+// as JSON (an array with an object per level).
+//
+// Usage:
+//
+//	go run ./cmd/analyze [-json] level.json...
+package main
+
+import (
+	"connect-dots/game"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"go.uber.org/zap"
+)
+
+// report is the analysis of a level file.
+type report struct {
+	File string `json:"file"`
+	*game.Analysis
+}
+
+func main() {
+	var asJSON bool
+
+	flag.BoolVar(&asJSON, "json", false, "print the metrics as JSON")
+	flag.Parse()
+
+	log, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatal("Failed to create a zap logger", zap.Error(err))
+	}
+	defer log.Sync() //nolint
+
+	reports := []report{}
+	failed := 0
+	for _, f := range flag.Args() {
+		l, err := game.LoadFromFile(f)
+		if err != nil {
+			log.Error("Failed to load the level", zap.String("file", f), zap.Error(err))
+			failed++
+			continue
+		}
+
+		a, err := game.Analyze(l)
+		if err != nil {
+			log.Error("Failed to analyze the level", zap.String("file", f), zap.Error(err))
+			failed++
+			continue
+		}
+
+		if asJSON {
+			reports = append(reports, report{File: f, Analysis: a})
+		} else {
+			printText(os.Stdout, f, a)
+		}
+	}
+
+	if asJSON {
+		data, err := json.MarshalIndent(reports, "", "    ")
+		if err != nil {
+			log.Fatal("Failed to encode the metrics", zap.Error(err))
+		}
+		fmt.Println(string(data))
+	}
+
+	if failed > 0 {
+		log.Sync() //nolint
+		os.Exit(1)
+	}
+}
+
+// printText prints the metrics of a level as text.
+func printText(w io.Writer, file string, a *game.Analysis) {
+	solutions := fmt.Sprint(a.Solutions)
+	if a.Capped {
+		solutions = fmt.Sprintf("at least %d", a.Solutions)
+	}
+
+	fmt.Fprintf(w, "%s\n", file)
+	fmt.Fprintf(w, "  size:             %dx%d\n", a.Width, a.Height)
+	fmt.Fprintf(w, "  colors:           %d\n", a.Colors)
+	fmt.Fprintf(w, "  difficulty:       %s\n", a.Difficulty)
+	fmt.Fprintf(w, "  forced moves:     %d\n", a.ForcedMoves)
+	fmt.Fprintf(w, "  search nodes:     %d\n", a.Nodes)
+	fmt.Fprintf(w, "  branching factor: %.2f\n", a.BranchingFactor)
+	fmt.Fprintf(w, "  solutions:        %s\n", solutions)
+	fmt.Fprintf(w, "  pairs:\n")
+	for _, p := range a.Pairs {
+		fmt.Fprintf(w, "    %-8s distance %2d, path length %2d\n", p.Color, p.Distance, p.PathLength)
+	}
+}
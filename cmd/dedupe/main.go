@@ -0,0 +1,74 @@
+// Command dedupe looks for the duplicate levels stored under the data
+// directory: the levels which are identical, or equivalent up to a rotation,
+// a reflection or a permutation of the colors. It exits with status 1 if
+// any duplicate is found.
+//
+// Usage:
+//
+//	go run ./cmd/dedupe [-data data]
+package main
+
+import (
+	"connect-dots/game"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+)
+
+func main() {
+	var dir string
+
+	flag.StringVar(&dir, "data", "data", "the directory storing the levels")
+	flag.Parse()
+
+	log, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatal("Failed to create a zap logger", zap.Error(err))
+	}
+	defer log.Sync() //nolint
+
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*.json"))
+	if err != nil {
+		log.Fatal("Failed to list the level files", zap.Error(err))
+	}
+
+	type seen struct {
+		file  string
+		level *game.Level
+	}
+	first := make(map[string]seen)
+
+	failed, duplicates := 0, 0
+	for _, f := range files {
+		l, err := game.LoadFromFile(f)
+		if err != nil {
+			log.Error("Failed to load the level", zap.String("file", f), zap.Error(err))
+			failed++
+			continue
+		}
+
+		key := game.CanonicalKey(l)
+		s, ok := first[key]
+		if !ok {
+			first[key] = seen{file: f, level: l}
+			continue
+		}
+
+		kind := "equivalent to"
+		if s.level.SameDots(l) {
+			kind = "identical to"
+		}
+		fmt.Printf("%s: %s %s\n", f, kind, s.file)
+		duplicates++
+	}
+
+	fmt.Printf("%d levels, %d duplicates\n", len(files)-failed, duplicates)
+
+	if failed > 0 || duplicates > 0 {
+		log.Sync() //nolint
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,241 @@
+// Command pack fills the data directory with generated levels: for each
+// board size, N levels having a unique solution are written to
+// data/<n>/0.json..N-1.json, sorted by difficulty, so the game plays them
+// in this order. The levels only depend on the master seed. The sizes are
+// generated in parallel and a summary of the levels produced is printed
+// once they are written.
+//
+// Usage:
+//
+//	go run ./cmd/pack [-data data] [-sizes 5-10] [-n 10] [-seed 1] [-f] [-report file]
+package main
+
+import (
+	"connect-dots/game"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxFailures is the number of the levels which may fail to be generated
+// for a size, per level written.
+const maxFailures = 10
+
+// pack is the set of the levels of a board size.
+type pack struct {
+	size   int32
+	levels []*game.Level
+
+	// The number of the levels which failed to be generated.
+	failures int
+
+	elapsed time.Duration
+	err     error
+}
+
+func main() {
+	var (
+		dir    string
+		sizes  string
+		count  int
+		seed   int64
+		force  bool
+		report string
+	)
+
+	flag.StringVar(&dir, "data", "data", "the directory storing the levels")
+	flag.StringVar(&sizes, "sizes", "5-10", "the board sizes (a range like 5-10 or a list like 5,7)")
+	flag.IntVar(&count, "n", 10, "the number of levels per size")
+	flag.Int64Var(&seed, "seed", 1, "the master seed")
+	flag.BoolVar(&force, "f", false, "replace the levels already in the data directory")
+	flag.StringVar(&report, "report", "", "the file the summary is written to (besides the standard output)")
+	flag.Parse()
+
+	log, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatal("Failed to create a zap logger", zap.Error(err))
+	}
+	defer log.Sync() //nolint
+
+	ss, err := parseSizes(sizes)
+	if err != nil {
+		log.Fatal("Invalid sizes", zap.Error(err))
+	}
+	if count <= 0 {
+		log.Fatal("Invalid value for n", zap.Int("n", count))
+	}
+
+	for _, size := range ss {
+		if existing, _ := filepath.Glob(filepath.Join(dir, fmt.Sprint(size), "*.json")); len(existing) > 0 && !force {
+			log.Fatal("The data directory already has levels of this size (use -f to replace them)",
+				zap.Int32("size", size))
+		}
+	}
+
+	packs := make([]*pack, len(ss))
+	var wg sync.WaitGroup
+	for i, size := range ss {
+		wg.Add(1)
+		go func(i int, size int32) {
+			defer wg.Done()
+			packs[i] = build(size, count, seed)
+		}(i, size)
+	}
+	wg.Wait()
+
+	failed := false
+	for _, p := range packs {
+		if p.err == nil {
+			p.err = write(dir, p)
+		}
+		if p.err != nil {
+			log.Error("Failed to build the pack", zap.Int32("size", p.size), zap.Error(p.err))
+			failed = true
+		}
+	}
+
+	out := io.Writer(os.Stdout)
+	if report != "" {
+		f, err := os.Create(report)
+		if err != nil {
+			log.Fatal("Failed to create the report", zap.Error(err))
+		}
+		defer f.Close()
+		out = io.MultiWriter(os.Stdout, f)
+	}
+	summary(out, dir, seed, packs)
+
+	if failed {
+		log.Sync() //nolint
+		os.Exit(1)
+	}
+}
+
+// parseSizes parses a range of board sizes (5-10) or a list (5,7,9).
+func parseSizes(s string) ([]int32, error) {
+	var sizes []int32
+	if bounds := strings.Split(s, "-"); len(bounds) == 2 {
+		from, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		to, err := strconv.Atoi(bounds[1])
+		if err != nil {
+			return nil, err
+		}
+		for size := from; size <= to; size++ {
+			sizes = append(sizes, int32(size))
+		}
+	} else {
+		for _, f := range strings.Split(s, ",") {
+			size, err := strconv.Atoi(f)
+			if err != nil {
+				return nil, err
+			}
+			sizes = append(sizes, int32(size))
+		}
+	}
+
+	if len(sizes) == 0 {
+		return nil, fmt.Errorf("No sizes in %s", s)
+	}
+	return sizes, nil
+}
+
+// build generates the levels of a size, sorted by difficulty. The seeds of
+// the levels are drawn from the master seed and the size, and the number
+// of colors varies around the size of the board.
+func build(size int32, count int, seed int64) *pack {
+	start := time.Now()
+	p := &pack{size: size}
+	r := rand.New(rand.NewSource(seed*1000 + int64(size)))
+
+	for len(p.levels) < count {
+		if p.failures > maxFailures*count {
+			p.err = fmt.Errorf("Failed to generate %d levels of size %d", count, size)
+			break
+		}
+
+		colors := int(size) - 1 + r.Intn(3)
+		l, err := game.Generate(size, colors, r.Int63(), game.WithUniqueSolution())
+		if err != nil {
+			p.failures++
+			continue
+		}
+		p.levels = append(p.levels, l)
+	}
+
+	sort.SliceStable(p.levels, func(i, j int) bool {
+		return p.levels[i].Difficulty < p.levels[j].Difficulty
+	})
+	p.elapsed = time.Since(start)
+	return p
+}
+
+// write saves the levels of a pack as data/<n>/0.json..N-1.json, removing
+// the level files numbered after them (the game would play them next).
+func write(dir string, p *pack) error {
+	packDir := filepath.Join(dir, fmt.Sprint(p.size))
+	if err := os.MkdirAll(packDir, 0755); err != nil {
+		return err
+	}
+
+	for i, l := range p.levels {
+		if err := l.SaveToFile(filepath.Join(packDir, fmt.Sprintf("%d.json", i))); err != nil {
+			return err
+		}
+	}
+
+	for i := len(p.levels); ; i++ {
+		path := filepath.Join(packDir, fmt.Sprintf("%d.json", i))
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+}
+
+// summary writes the report of the levels produced.
+func summary(w io.Writer, dir string, seed int64, packs []*pack) {
+	fmt.Fprintf(w, "Levels written to %s (master seed %d)\n", dir, seed)
+	for _, p := range packs {
+		if p.err != nil {
+			fmt.Fprintf(w, "%dx%d: failed: %v\n", p.size, p.size, p.err)
+			continue
+		}
+
+		grades := make(map[game.Difficulty]int)
+		minColors, maxColors := 0, 0
+		for i, l := range p.levels {
+			grades[l.Difficulty]++
+			colors := len(l.Dots) / 2
+			if i == 0 || colors < minColors {
+				minColors = colors
+			}
+			if colors > maxColors {
+				maxColors = colors
+			}
+		}
+
+		var gs []string
+		for d := game.Easy; d <= game.Expert; d++ {
+			gs = append(gs, fmt.Sprintf("%d %s", grades[d], d))
+		}
+
+		fmt.Fprintf(w, "%dx%d: %d levels, %d-%d colors, %s (%d failed, %s)\n",
+			p.size, p.size, len(p.levels), minColors, maxColors,
+			strings.Join(gs, ", "), p.failures, p.elapsed.Round(time.Millisecond))
+	}
+}
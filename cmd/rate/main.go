@@ -0,0 +1,72 @@
+// Command rate computes the difficulty of the levels stored under the data
+// directory and rewrites the level files with the computed grade.
+//
+// Usage:
+//
+//	go run ./cmd/rate [-data data] [-n]
+package main
+
+import (
+	"connect-dots/game"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+)
+
+func main() {
+	var (
+		dir    string
+		dryRun bool
+	)
+
+	flag.StringVar(&dir, "data", "data", "the directory storing the levels")
+	flag.BoolVar(&dryRun, "n", false, "print the grades without rewriting the files")
+	flag.Parse()
+
+	log, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatal("Failed to create a zap logger", zap.Error(err))
+	}
+	defer log.Sync() //nolint
+
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*.json"))
+	if err != nil {
+		log.Fatal("Failed to list the level files", zap.Error(err))
+	}
+
+	failed := 0
+	for _, f := range files {
+		l, err := game.LoadFromFile(f)
+		if err != nil {
+			log.Error("Failed to load the level", zap.String("file", f), zap.Error(err))
+			failed++
+			continue
+		}
+
+		d, err := game.Rate(l)
+		if err != nil {
+			log.Error("Failed to rate the level", zap.String("file", f), zap.Error(err))
+			failed++
+			continue
+		}
+
+		fmt.Printf("%s: %s\n", f, d)
+		if dryRun {
+			continue
+		}
+
+		l.Difficulty = d
+		if err := l.SaveToFile(f); err != nil {
+			log.Error("Failed to save the level", zap.String("file", f), zap.Error(err))
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		log.Sync() //nolint
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,177 @@
+// Command variants writes transformed variants of the given levels into
+// a pack: the level files of a board size, data/<n>/<m>.json. The variants
+// are numbered after the files already in the pack, and the variants
+// identical to a level of the pack (or to the level transformed) are skipped.
+//
+// Usage:
+//
+//	go run ./cmd/variants [-data data] [-t rotate90,mirror,...] [-recolor seed] level.json...
+package main
+
+import (
+	"connect-dots/game"
+	"connect-dots/graphics"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// transformations are the transformations known by name.
+var transformations = map[string]func(*game.Level) (*game.Level, error){
+	"rotate90":  func(l *game.Level) (*game.Level, error) { return l.Rotate(90) },
+	"rotate180": func(l *game.Level) (*game.Level, error) { return l.Rotate(180) },
+	"rotate270": func(l *game.Level) (*game.Level, error) { return l.Rotate(270) },
+	"mirror":    func(l *game.Level) (*game.Level, error) { return l.Mirror(), nil },
+	"transpose": func(l *game.Level) (*game.Level, error) { return l.Transpose(), nil },
+}
+
+func main() {
+	var (
+		dir     string
+		names   string
+		recolor int64
+	)
+
+	flag.StringVar(&dir, "data", "data", "the directory storing the levels")
+	flag.StringVar(&names, "t", "rotate90,rotate180,rotate270,mirror,transpose",
+		"the comma separated transformations to apply")
+	flag.Int64Var(&recolor, "recolor", 0, "the seed of a random recoloring of the variants (none if 0)")
+	flag.Parse()
+
+	log, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatal("Failed to create a zap logger", zap.Error(err))
+	}
+	defer log.Sync() //nolint
+
+	var ts []string
+	for _, name := range strings.Split(names, ",") {
+		if _, ok := transformations[name]; !ok {
+			log.Fatal("Unknown transformation", zap.String("name", name))
+		}
+		ts = append(ts, name)
+	}
+
+	r := rand.New(rand.NewSource(recolor))
+
+	// the levels of the packs by board width, loaded when first needed
+	packs := make(map[int32][]*game.Level)
+
+	failed := 0
+	for _, f := range flag.Args() {
+		l, err := game.LoadFromFile(f)
+		if err != nil {
+			log.Error("Failed to load the level", zap.String("file", f), zap.Error(err))
+			failed++
+			continue
+		}
+
+		for _, name := range ts {
+			v, err := transformations[name](l)
+			if err == nil && recolor != 0 {
+				v, err = v.Recolor(shuffled(v, r))
+			}
+			if err != nil {
+				log.Error("Failed to transform the level", zap.String("file", f),
+					zap.String("transformation", name), zap.Error(err))
+				failed++
+				continue
+			}
+
+			pack, ok := packs[v.Width]
+			if !ok {
+				pack, err = loadPack(dir, v.Width)
+				if err != nil {
+					log.Error("Failed to load the pack", zap.Int32("width", v.Width), zap.Error(err))
+					failed++
+					continue
+				}
+				packs[v.Width] = pack
+			}
+
+			if l.SameDots(v) || contains(pack, v) {
+				fmt.Printf("%s %s: skipped (identical)\n", f, name)
+				continue
+			}
+
+			path := nextFile(dir, v.Width)
+			if err := v.SaveToFile(path); err != nil {
+				log.Error("Failed to save the level", zap.String("file", path), zap.Error(err))
+				failed++
+				continue
+			}
+			packs[v.Width] = append(pack, v)
+			fmt.Printf("%s %s: %s\n", f, name, path)
+		}
+	}
+
+	if failed > 0 {
+		log.Sync() //nolint
+		os.Exit(1)
+	}
+}
+
+// shuffled returns a random permutation of the colors of a level.
+func shuffled(l *game.Level, r *rand.Rand) map[graphics.Color]graphics.Color {
+	seen := make(map[graphics.Color]bool)
+	var colors []graphics.Color
+	for _, dot := range l.Dots {
+		if !seen[dot.Color] {
+			seen[dot.Color] = true
+			colors = append(colors, dot.Color)
+		}
+	}
+	sort.Slice(colors, func(i, j int) bool { return colors[i] < colors[j] })
+
+	m := make(map[graphics.Color]graphics.Color)
+	for i, j := range r.Perm(len(colors)) {
+		m[colors[i]] = colors[j]
+	}
+	return m
+}
+
+// contains checks if a level has the same dots as one of the levels.
+func contains(levels []*game.Level, l *game.Level) bool {
+	for _, other := range levels {
+		if other.SameDots(l) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadPack loads the level files of the pack of the given board width.
+func loadPack(dir string, width int32) ([]*game.Level, error) {
+	files, err := filepath.Glob(filepath.Join(dir, fmt.Sprint(width), "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var pack []*game.Level
+	for _, f := range files {
+		l, err := game.LoadFromFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load the level from file %s: %v", f, err)
+		}
+		pack = append(pack, l)
+	}
+	return pack, nil
+}
+
+// nextFile returns the path of the first level file missing from the pack
+// of the given board width.
+func nextFile(dir string, width int32) string {
+	pack := filepath.Join(dir, fmt.Sprint(width))
+	for m := 0; ; m++ {
+		path := filepath.Join(pack, fmt.Sprintf("%d.json", m))
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path
+		}
+	}
+}
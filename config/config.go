@@ -9,8 +9,15 @@ type Config struct {
 	// The height of the application window.
 	WindowHeight int32
 
-	// The size (the number of rows and columns) of the board.
-	Size int32
+	// The width (the number of columns) and the height
+	// (the number of rows) of the board.
+	Width  int32
+	Height int32
+
+	// The squares of the board which cannot be entered, and the walls
+	// between adjacent squares (given by the two squares they separate).
+	Blocked []sdl.Point
+	Walls   [][2]sdl.Point
 
 	// The size of a board square.
 	SquareSize int32
@@ -28,7 +35,8 @@ func New(opts ...Option) *Config {
 	cfg := &Config{
 		WindowWidth:  800,
 		WindowHeight: 600,
-		Size:         5,
+		Width:        5,
+		Height:       5,
 		SquareSize:   48,
 		DotRadius:    16,
 		Color:        &sdl.Color{R: 168, G: 168, B: 168, A: 255},
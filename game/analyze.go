@@ -0,0 +1,106 @@
+package game
+
+// analyzeLimit is the number of solutions Analyze stops counting at.
+const analyzeLimit = 100
+
+// Analysis stores the metrics of a level (see Analyze).
+type Analysis struct {
+	Width  int32 `json:"width"`
+	Height int32 `json:"height"`
+	Colors int   `json:"colors"`
+
+	// The name of the difficulty computed by Rate.
+	Difficulty string `json:"difficulty"`
+
+	// The metrics of each pair of dots, in the order the colors
+	// appear in the level.
+	Pairs []PairAnalysis `json:"pairs"`
+
+	// The number of the moves forced from the start (the moves found
+	// by the ForcedMove technique before it gets stuck).
+	ForcedMoves int `json:"forcedMoves"`
+
+	// The number of the nodes explored by the backtracking search which
+	// counts the solutions, and the average number of the moves tried
+	// at a node (1 when the search never has to guess).
+	Nodes           int64   `json:"nodes"`
+	BranchingFactor float64 `json:"branchingFactor"`
+
+	// The number of solutions (counted up to analyzeLimit,
+	// in which case Capped is set).
+	Solutions int  `json:"solutions"`
+	Capped    bool `json:"capped"`
+}
+
+// PairAnalysis stores the metrics of a pair of dots.
+type PairAnalysis struct {
+	Color string `json:"color"`
+
+	// The Manhattan distance between the dots.
+	Distance int32 `json:"distance"`
+
+	// The number of squares covered by the path of the first solution
+	// found, its dots included (0 if the level has no solution).
+	PathLength int `json:"pathLength"`
+}
+
+// Analyze computes the metrics of a level, which help to understand
+// why a level feels harder than another one.
+func Analyze(level *Level) (*Analysis, error) {
+	s, err := newSolver(level)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Analysis{Width: level.Width, Height: level.Height, Colors: len(s.pairs)}
+	for _, p := range s.pairs {
+		dx, dy := p.start.Location.X-p.end.Location.X, p.start.Location.Y-p.end.Location.Y
+		if dx < 0 {
+			dx = -dx
+		}
+		if dy < 0 {
+			dy = -dy
+		}
+		a.Pairs = append(a.Pairs, PairAnalysis{Color: p.color.String(), Distance: dx + dy})
+	}
+
+	var first []*Path
+	if s.feasible() {
+		a.Capped = s.search(func() bool {
+			if a.Solutions == 0 {
+				first = s.paths()
+			}
+			a.Solutions++
+			return a.Solutions >= analyzeLimit
+		})
+	}
+	a.Nodes = s.nodes
+	if s.branching > 0 {
+		a.BranchingFactor = float64(s.branches) / float64(s.branching)
+	}
+	for i, path := range first {
+		a.Pairs[i].PathLength = len(path.Lines) + 1
+	}
+
+	d, err := newDeducer(level)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		ded, ok := d.forcedMove()
+		if !ok {
+			break
+		}
+		d.apply(ded)
+		a.ForcedMoves++
+	}
+
+	grade, solved := d.grade()
+	if !solved && a.Solutions == 0 {
+		a.Difficulty = "no solution"
+	} else {
+		a.Difficulty = grade.String()
+	}
+
+	return a, nil
+}
@@ -0,0 +1,43 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyze(t *testing.T) {
+	l, err := Generate(6, 5, 9, WithUniqueSolution())
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	a, err := Analyze(l)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, a.Colors)
+	assert.Equal(t, l.Difficulty.String(), a.Difficulty)
+	assert.Equal(t, 1, a.Solutions)
+	assert.False(t, a.Capped)
+	assert.True(t, a.BranchingFactor >= 1)
+
+	covered := 0
+	for _, p := range a.Pairs {
+		assert.True(t, p.PathLength > int(p.Distance))
+		covered += p.PathLength
+	}
+	assert.Equal(t, 36, covered)
+}
+
+func TestAnalyzeSeveralSolutions(t *testing.T) {
+	l := levelOf(t, 3, `[
+		{"x": 0, "y": 0, "color": "red"},
+		{"x": 2, "y": 2, "color": "red"}]`)
+
+	a, err := Analyze(l)
+	assert.Nil(t, err)
+	assert.Equal(t, []PairAnalysis{{Color: "red", Distance: 4, PathLength: 9}}, a.Pairs)
+	assert.Equal(t, 2, a.Solutions)
+	assert.Equal(t, 0, a.ForcedMoves)
+	assert.Equal(t, "expert", a.Difficulty)
+	assert.True(t, a.BranchingFactor > 1)
+}
@@ -0,0 +1,109 @@
+package game
+
+import (
+	"connect-dots/graphics"
+
+	"go.uber.org/zap"
+)
+
+// assistLine is a line drawn by the assist mode.
+type assistLine struct {
+	// The path the line was added to.
+	path *Path
+
+	// The line.
+	line Line
+
+	// True if the line completed the path.
+	completed bool
+}
+
+// assistBatch stores the lines drawn at once by the assist mode,
+// so they can be undone as a unit.
+type assistBatch struct {
+	lines []assistLine
+}
+
+// autoComplete extends the paths having a single legal continuation
+// (see ForcedMove) until no move is forced anymore. It runs after
+// each move of the player when the assist mode is on.
+func (g *Game) autoComplete() {
+	if !g.assist || g.level == nil || g.Completed {
+		return
+	}
+
+	d, err := newBoardDeducer(g.level, g.board)
+	if err != nil {
+		g.log.Debug("Failed to look for the forced moves", zap.Error(err))
+		return
+	}
+
+	batch := &assistBatch{}
+	for {
+		ded, ok := d.forcedMove()
+		if !ok {
+			break
+		}
+
+		path := g.pathEndingAt(ded.From, ded.Color)
+		if path == nil {
+			break
+		}
+
+		// the path is drawn on the board from this side from now on
+		d.apply(ded)
+		d.grows[ded.pair] = ded.side
+
+		completed := g.extendPath(path, ded.From, ded.To, ded.Color)
+		batch.lines = append(batch.lines, assistLine{
+			path:      path,
+			line:      Line{From: ded.From, To: ded.To, Color: ded.Color},
+			completed: completed,
+		})
+	}
+
+	if len(batch.lines) == 0 {
+		return
+	}
+
+	g.assisted = batch
+	g.AutoMoves += int32(len(batch.lines))
+	g.checkCompleted()
+}
+
+// UndoAssist erases the lines drawn by the assist mode
+// after the last move of the player.
+func (g *Game) UndoAssist() {
+	if g.assisted == nil || g.state.editingPath {
+		return
+	}
+
+	g.cancelHint()
+
+	lines := g.assisted.lines
+	for i := len(lines) - 1; i >= 0; i-- {
+		al := lines[i]
+		if al.completed {
+			if other, ok := g.board.Paths[*al.path.EndDot]; ok {
+				other.EndDot = nil
+			}
+			al.path.EndDot = nil
+		} else {
+			*(g.board.ColorAt(al.line.To.X, al.line.To.Y)) = graphics.NoColor
+		}
+
+		al.path.RemoveLine(al.line.From, al.line.To)
+		g.deleteLine(al.line)
+	}
+
+	g.AutoMoves -= int32(len(lines))
+	g.assisted = nil
+	g.coverage = g.board.Coverage()
+	g.updateDeadEnds()
+}
+
+// ToggleAssist switches the assist mode on or off.
+func (g *Game) ToggleAssist() {
+	g.assist = !g.assist
+	g.assisted = nil
+}
@@ -0,0 +1,70 @@
+package game
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// backgroundSolve is a solve of a level running in its own goroutines,
+// so the game loop keeps pumping the SDL events meanwhile.
+type backgroundSolve struct {
+	cancel context.CancelFunc
+
+	// Closed when the solve ends.
+	done chan struct{}
+
+	// The progress of the solve (updated atomically).
+	nodes int64
+	depth int64
+
+	// The result of the solve (read only after done is closed).
+	paths     []*Path
+	offending *Path
+	err       error
+}
+
+// startSolve starts solving a level in the background, keeping
+// the paths drawn by the player (see findHint).
+func startSolve(level *Level, drawn []*Path) *backgroundSolve {
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &backgroundSolve{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(b.done)
+		b.paths, b.offending, b.err = findHint(ctx, level, drawn,
+			WithProgress(func(p Progress) {
+				atomic.StoreInt64(&b.nodes, p.Nodes)
+				atomic.StoreInt64(&b.depth, int64(p.Depth))
+			}, 50*time.Millisecond))
+	}()
+
+	return b
+}
+
+// finished checks (without blocking) if the solve ended.
+func (b *backgroundSolve) finished() bool {
+	select {
+	case <-b.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// progress returns the latest progress report of the solve.
+func (b *backgroundSolve) progress() Progress {
+	return Progress{
+		Nodes: atomic.LoadInt64(&b.nodes),
+		Depth: int(atomic.LoadInt64(&b.depth)),
+	}
+}
+
+// stop cancels the solve and waits for its goroutines to end.
+func (b *backgroundSolve) stop() {
+	b.cancel()
+	<-b.done
+}
@@ -37,6 +37,13 @@ func NewLine(from, to Coordinate, c graphics.Color) Line {
 	return Line{from, to, c}
 }
 
+// Wall separates two adjacent squares of the board:
+// no line may join the squares (in either direction).
+type Wall struct {
+	From Coordinate
+	To   Coordinate
+}
+
 // Dot represents a dot from the board.
 type Dot struct {
 	// The coordinates of the dot.
@@ -99,24 +106,74 @@ type Board struct {
 	// The states (colors) of the board squares/cells.
 	colors []graphics.Color
 
-	// The size of the board.
-	size int32
+	// The width (the number of columns) and the height
+	// (the number of rows) of the board.
+	width  int32
+	height int32
+
+	// The squares which cannot be entered (see SetShape)
+	// and the walls between adjacent squares.
+	blocked []bool
+	walls   map[Wall]bool
 }
 
-// NewBoard creates a board.
-func NewBoard(size int32) *Board {
-	cs := make([]graphics.Color, size*size)
+// NewBoard creates a board of the given width and height.
+func NewBoard(width, height int32) *Board {
+	cs := make([]graphics.Color, width*height)
 	for i := range cs {
 		cs[i] = graphics.NoColor
 	}
 
 	return &Board{
-		Paths:  make(map[Dot]*Path),
-		colors: cs,
-		size:   size,
+		Paths:   make(map[Dot]*Path),
+		colors:  cs,
+		width:   width,
+		height:  height,
+		blocked: make([]bool, width*height),
+		walls:   make(map[Wall]bool),
 	}
 }
 
+// SetShape sets the squares which cannot be entered (the holes of an
+// irregular board) and the walls between adjacent squares.
+func (b *Board) SetShape(blocked []Coordinate, walls []Wall) {
+	for i := range b.blocked {
+		b.blocked[i] = false
+	}
+	for _, c := range blocked {
+		b.blocked[c.X*b.height+c.Y] = true
+	}
+
+	for w := range b.walls {
+		delete(b.walls, w)
+	}
+	for _, w := range walls {
+		b.walls[w] = true
+		b.walls[Wall{w.To, w.From}] = true
+	}
+}
+
+// Blocked checks if the square at the given board coordinates
+// cannot be entered.
+func (b *Board) Blocked(x, y int32) bool {
+	return b.blocked[x*b.height+y]
+}
+
+// Open checks if a line may join two squares: the squares must be
+// orthogonal neighbours inside the board, none of them blocked, and
+// no wall may separate them.
+func (b *Board) Open(from, to Coordinate) bool {
+	inside := func(c Coordinate) bool {
+		return c.X >= 0 && c.Y >= 0 && c.X < b.width && c.Y < b.height
+	}
+	if !inside(from) || !inside(to) || b.Blocked(from.X, from.Y) || b.Blocked(to.X, to.Y) {
+		return false
+	}
+
+	dx, dy := to.X-from.X, to.Y-from.Y
+	return dx*dx+dy*dy == 1 && !b.walls[Wall{from, to}]
+}
+
 // InitPath initilizes the paths.
 func (b *Board) InitPath(dot Dot) {
 	b.Paths[dot] = &Path{StartDot: &Dot{
@@ -129,7 +186,7 @@ func (b *Board) InitPath(dot Dot) {
 // ColorAt returns a pointer to the graphics.Color object
 // corresponding to the given board coordinates.
 func (b *Board) ColorAt(x, y int32) *graphics.Color {
-	return &(b.colors[x*b.size+y])
+	return &(b.colors[x*b.height+y])
 }
 
 // InitPaths initializes the map of the paths:
@@ -144,6 +201,45 @@ func (b *Board) InitPaths(dots []Dot) {
 	}
 }
 
+// AddPath stores a complete path (e.g. a solver result) on the board:
+// the path is kept under its start dot, the end dot refers back to it
+// and the squares the path covers take its color.
+func (b *Board) AddPath(p *Path) {
+	b.Paths[*p.StartDot] = p
+	b.Paths[*p.EndDot] = &Path{
+		StartDot: &Dot{Location: p.EndDot.Location, Color: p.EndDot.Color},
+		EndDot:   &Dot{Location: p.StartDot.Location, Color: p.StartDot.Color},
+	}
+
+	*(b.ColorAt(p.StartDot.Location.X, p.StartDot.Location.Y)) = p.StartDot.Color
+	for _, l := range p.Lines {
+		*(b.ColorAt(l.To.X, l.To.Y)) = l.Color
+	}
+}
+
+// NewSolvedBoard creates a board covered by the given (complete) paths.
+func NewSolvedBoard(width, height int32, paths []*Path) *Board {
+	b := NewBoard(width, height)
+	for _, p := range paths {
+		b.AddPath(p)
+	}
+	return b
+}
+
+// Diff returns the coordinates of the squares whose colors differ
+// on the two boards. The boards must have the same dimensions.
+func (b *Board) Diff(o *Board) []Coordinate {
+	var diff []Coordinate
+	for x := int32(0); x < b.width; x++ {
+		for y := int32(0); y < b.height; y++ {
+			if *b.ColorAt(x, y) != *o.ColorAt(x, y) {
+				diff = append(diff, Coordinate{x, y})
+			}
+		}
+	}
+	return diff
+}
+
 // Clear removes the paths and resets the slice of colors.
 func (b *Board) Clear() {
 	for path := range b.Paths {
@@ -155,11 +251,24 @@ func (b *Board) Clear() {
 	}
 }
 
-// Coverage returns the number of covered squares.
+// Squares returns the number of squares of the board
+// which may be covered (the blocked squares left out).
+func (b *Board) Squares() int32 {
+	count := b.width * b.height
+	for _, blocked := range b.blocked {
+		if blocked {
+			count--
+		}
+	}
+	return count
+}
+
+// Coverage returns the number of covered squares
+// (the blocked squares are never covered).
 func (b *Board) Coverage() int32 {
 	count := int32(0)
-	for _, c := range b.colors {
-		if c != graphics.NoColor {
+	for i, c := range b.colors {
+		if c != graphics.NoColor && !b.blocked[i] {
 			count++
 		}
 	}
@@ -168,8 +277,8 @@ func (b *Board) Coverage() int32 {
 
 func (b *Board) Dump() {
 	fmt.Println("Board:")
-	for y := 0; y < int(b.size); y++ {
-		for x := 0; x < int(b.size); x++ {
+	for y := 0; y < int(b.height); y++ {
+		for x := 0; x < int(b.width); x++ {
 			fmt.Printf("|% 2d| ", int(*b.ColorAt(int32(x), int32(y))))
 		}
 		fmt.Println()
@@ -0,0 +1,156 @@
+package game
+
+import (
+	"connect-dots/graphics"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// symmetry maps a square of a board of the given width and height
+// to its image by a symmetry of the board.
+type symmetry func(c Coordinate, w, h int32) Coordinate
+
+// The symmetries of a board (the rotations by 90 and 270 degrees and the
+// reflections along a diagonal swap the width and the height).
+func identity(c Coordinate, w, h int32) Coordinate      { return Coordinate{c.X, c.Y} }
+func rotate90(c Coordinate, w, h int32) Coordinate      { return Coordinate{h - 1 - c.Y, c.X} }
+func rotate180(c Coordinate, w, h int32) Coordinate     { return Coordinate{w - 1 - c.X, h - 1 - c.Y} }
+func rotate270(c Coordinate, w, h int32) Coordinate     { return Coordinate{c.Y, w - 1 - c.X} }
+func mirror(c Coordinate, w, h int32) Coordinate        { return Coordinate{w - 1 - c.X, c.Y} }
+func transpose(c Coordinate, w, h int32) Coordinate     { return Coordinate{c.Y, c.X} }
+func flip(c Coordinate, w, h int32) Coordinate          { return Coordinate{c.X, h - 1 - c.Y} }
+func antiTranspose(c Coordinate, w, h int32) Coordinate { return Coordinate{h - 1 - c.Y, w - 1 - c.X} }
+
+// dimensions returns the width and the height of the image of a board:
+// the images of two opposite corners are opposite corners of the image.
+func (s symmetry) dimensions(w, h int32) (int32, int32) {
+	a, b := s(Coordinate{0, 0}, w, h), s(Coordinate{w - 1, h - 1}, w, h)
+	if a.X < b.X {
+		a.X = b.X
+	}
+	if a.Y < b.Y {
+		a.Y = b.Y
+	}
+	return a.X + 1, a.Y + 1
+}
+
+// symmetries are the 8 symmetries of a board: the rotations by 0, 90,
+// 180 and 270 degrees (clockwise), with or without a reflection.
+var symmetries = []symmetry{
+	identity, rotate90, rotate180, rotate270,
+	mirror, transpose, flip, antiTranspose,
+}
+
+// Canonical returns the canonical form of a level: the same for all the
+// levels which only differ by a rotation, a reflection or a permutation of
+// the colors. Among the images of the level by the symmetries of the
+// board, the canonical form is the one with the smallest key (see
+// CanonicalKey), its dots (and its blocked squares and walls) sorted row
+// by row and its colors given in the order of the palette as they appear.
+func Canonical(l *Level) *Level {
+	var best *Level
+	var key string
+	for _, s := range symmetries {
+		t := l.transformed(s)
+		t.Dots = normalized(t.Dots)
+		sortShape(t)
+		if k := levelKey(t); best == nil || k < key {
+			best, key = t, k
+		}
+	}
+
+	return best
+}
+
+// CanonicalKey returns a string identifying the canonical form of a level:
+// two levels are equivalent under symmetry and recoloring if and only if
+// their keys are equal.
+func CanonicalKey(l *Level) string {
+	return levelKey(Canonical(l))
+}
+
+// normalized sorts the dots row by row and recolors them in the order
+// of the palette as they appear.
+func normalized(dots []Dot) []Dot {
+	sort.Slice(dots, func(i, j int) bool {
+		a, b := dots[i].Location, dots[j].Location
+		return a.Y < b.Y || (a.Y == b.Y && a.X < b.X)
+	})
+
+	colors := make(map[graphics.Color]graphics.Color)
+	for i, dot := range dots {
+		c, ok := colors[dot.Color]
+		if !ok {
+			c = paletteColor(len(colors))
+			colors[dot.Color] = c
+		}
+		dots[i].Color = c
+	}
+	return dots
+}
+
+// paletteColor returns the i-th color of the palette, followed by the
+// colors left out of the palette.
+func paletteColor(i int) graphics.Color {
+	if i < len(palette) {
+		return palette[i]
+	}
+
+	i -= len(palette)
+	for c := range graphics.Colors {
+		if !inPalette(graphics.Color(c)) {
+			if i == 0 {
+				return graphics.Color(c)
+			}
+			i--
+		}
+	}
+	return graphics.NoColor
+}
+
+func inPalette(c graphics.Color) bool {
+	for _, p := range palette {
+		if p == c {
+			return true
+		}
+	}
+	return false
+}
+
+// sortShape sorts the blocked squares of a level row by row, and its walls
+// by their first square (the first of their squares row by row).
+func sortShape(l *Level) {
+	before := func(a, b Coordinate) bool {
+		return a.Y < b.Y || (a.Y == b.Y && a.X < b.X)
+	}
+
+	sort.Slice(l.Blocked, func(i, j int) bool { return before(l.Blocked[i], l.Blocked[j]) })
+
+	for i, w := range l.Walls {
+		if before(w.To, w.From) {
+			l.Walls[i] = Wall{w.To, w.From}
+		}
+	}
+	sort.Slice(l.Walls, func(i, j int) bool {
+		a, b := l.Walls[i], l.Walls[j]
+		return before(a.From, b.From) || (a.From == b.From && before(a.To, b.To))
+	})
+}
+
+// levelKey encodes the dimensions of a board, its shape and its dots
+// (in their order).
+func levelKey(l *Level) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%dx%d", l.Width, l.Height)
+	for _, c := range l.Blocked {
+		fmt.Fprintf(&b, ";#%d,%d", c.X, c.Y)
+	}
+	for _, w := range l.Walls {
+		fmt.Fprintf(&b, ";|%d,%d,%d,%d", w.From.X, w.From.Y, w.To.X, w.To.Y)
+	}
+	for _, dot := range l.Dots {
+		fmt.Fprintf(&b, ";%d,%d,%d", dot.Location.X, dot.Location.Y, int(dot.Color))
+	}
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package game
+
+import (
+	"connect-dots/graphics"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonical(t *testing.T) {
+	l := levelOf(t, 5, `[
+		{"x": 0, "y": 0, "color": "red"},
+		{"x": 4, "y": 0, "color": "red"},
+		{"x": 1, "y": 2, "color": "blue"},
+		{"x": 3, "y": 4, "color": "blue"}]`)
+
+	// transposed and recolored
+	other := levelOf(t, 5, `[
+		{"x": 0, "y": 0, "color": "green"},
+		{"x": 0, "y": 4, "color": "green"},
+		{"x": 2, "y": 1, "color": "yellow"},
+		{"x": 4, "y": 3, "color": "yellow"}]`)
+
+	assert.Equal(t, CanonicalKey(l), CanonicalKey(other))
+	assert.Equal(t, Canonical(l), Canonical(other))
+
+	c := Canonical(l)
+	assert.Equal(t, Dot{Location: Coordinate{0, 0}, Color: graphics.Red}, c.Dots[0])
+
+	different := levelOf(t, 5, `[
+		{"x": 0, "y": 0, "color": "red"},
+		{"x": 4, "y": 0, "color": "red"},
+		{"x": 1, "y": 2, "color": "blue"},
+		{"x": 3, "y": 3, "color": "blue"}]`)
+	assert.NotEqual(t, CanonicalKey(l), CanonicalKey(different))
+}
+
+func TestCanonicalSymmetries(t *testing.T) {
+	l, err := Generate(6, 5, 3)
+	assert.Nil(t, err)
+
+	key := CanonicalKey(l)
+	for i, s := range symmetries {
+		image := &Level{Width: l.Width, Height: l.Height}
+		for _, dot := range l.Dots {
+			// shift the colors along the palette too
+			clr := palette[(int(dot.Color)+i)%len(palette)]
+			image.Dots = append(image.Dots, Dot{Location: s(dot.Location, l.Width, l.Height), Color: clr})
+		}
+		assert.Equal(t, key, CanonicalKey(image))
+	}
+}
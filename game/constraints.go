@@ -0,0 +1,238 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Symmetry is a symmetry required of the layout of the dots
+// of a generated level.
+type Symmetry int
+
+const (
+	// The dots may be anywhere.
+	NoSymmetry Symmetry = iota
+	// The layout is unchanged by a rotation by 180 degrees.
+	RotationalSymmetry
+	// The layout is unchanged by a reflection left to right.
+	MirrorSymmetry
+)
+
+// Constraints are the requirements a generated level must meet
+// (see WithConstraints). The zero value requires nothing.
+type Constraints struct {
+	// The minimum and the maximum number of squares covered by a path
+	// of the solution, its dots included (0 for minPathLength and no
+	// maximum).
+	MinPathLength int
+	MaxPathLength int
+
+	// The number of colors (0 for the number given to Generate).
+	Colors int
+
+	// True if the two dots of a color must not be orthogonal neighbours.
+	NoAdjacentDots bool
+
+	// The symmetry of the layout of the dots.
+	Symmetry Symmetry
+
+	// The difficulty of the level (nil for any difficulty).
+	Difficulty *Difficulty
+}
+
+// WithConstraints makes Generate emit only the levels meeting
+// the constraints.
+func WithConstraints(c Constraints) GenerateOption {
+	return func(cfg *generateConfig) {
+		cfg.constraints = c
+	}
+}
+
+// Constraint identifies a constraint of the generated levels.
+type Constraint int
+
+const (
+	// The lengths of the paths (see Constraints.MinPathLength).
+	PathLengthConstraint Constraint = iota
+	// The number of colors.
+	ColorsConstraint
+	// The dots of a color must not be neighbours.
+	AdjacentDotsConstraint
+	// The symmetry of the layout of the dots.
+	SymmetryConstraint
+	// The difficulty of the level.
+	DifficultyConstraint
+	// The uniqueness of the solution (see WithUniqueSolution).
+	UniquenessConstraint
+)
+
+var constraintNames = []string{
+	"path length",
+	"colors",
+	"adjacent dots",
+	"symmetry",
+	"difficulty",
+	"unique solution",
+}
+
+// String returns the name of the constraint.
+func (c Constraint) String() string {
+	if c >= 0 && int(c) < len(constraintNames) {
+		return constraintNames[c]
+	}
+	return fmt.Sprintf("constraint %d", int(c))
+}
+
+// ConstraintError is returned by Generate when it fails to meet
+// the constraints: it reports the constraint which failed the most often.
+type ConstraintError struct {
+	Size   int32
+	Colors int
+
+	// The constraint which failed.
+	Constraint Constraint
+
+	// The number of the attempts to generate the level (0 if the
+	// constraints cannot be met at all on a board of this size).
+	Attempts int
+}
+
+func (e *ConstraintError) Error() string {
+	if e.Attempts == 0 {
+		return fmt.Sprintf("Failed to generate a level of size %d with %d colors: the %s constraint cannot be met",
+			e.Size, e.Colors, e.Constraint)
+	}
+	return fmt.Sprintf("Failed to generate a level of size %d with %d colors: the %s constraint failed in %d attempts",
+		e.Size, e.Colors, e.Constraint, e.Attempts)
+}
+
+// lengths returns the minimum and the maximum path length
+// (0 for no maximum).
+func (c *Constraints) lengths() (int, int) {
+	min := c.MinPathLength
+	if min < minPathLength {
+		min = minPathLength
+	}
+	return min, c.MaxPathLength
+}
+
+// fits checks if n squares can be split into the given number of pieces
+// of lengths between min and max (no maximum if 0).
+func fits(n, pieces, min, max int) bool {
+	return pieces > 0 && pieces*min <= n && (max == 0 || n <= pieces*max)
+}
+
+// check returns the first constraint not met by the paths of a level
+// (the ones checked without solving the level), or false if they are met.
+func (c *Constraints) check(size int32, paths [][]int) (Constraint, bool) {
+	min, max := c.lengths()
+	for _, path := range paths {
+		if len(path) < min || (max > 0 && len(path) > max) {
+			return PathLengthConstraint, true
+		}
+	}
+
+	if c.NoAdjacentDots {
+		for _, path := range paths {
+			a, b := int32(path[0]), int32(path[len(path)-1])
+			dx, dy := a/size-b/size, a%size-b%size
+			if dx*dx+dy*dy == 1 {
+				return AdjacentDotsConstraint, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// newConstrainedPartition returns a random partition of the board meeting
+// the path length and the symmetry constraints.
+//
+// The symmetric layouts are grown on the left half of the board only, and
+// copied on the right half by the symmetry. On the boards of odd size, the
+// middle column is cut into pieces unchanged by the symmetry (for the
+// rotation, a single piece or three pieces, the outer ones swapped).
+func newConstrainedPartition(size int32, colors int, c *Constraints, r *rand.Rand) (*partition, error) {
+	min, max := c.lengths()
+	n := int(size * size)
+	fail := func(constraint Constraint) error {
+		return &ConstraintError{Size: size, Colors: colors, Constraint: constraint}
+	}
+
+	if c.Symmetry == NoSymmetry {
+		if !fits(n, colors, min, max) {
+			return nil, fail(PathLengthConstraint)
+		}
+		return newPartition(size, hamiltonianPath(size, r), randomLengths(n, colors, min, max, r), min, max), nil
+	}
+
+	half := size / 2
+	var middle []int
+	if size%2 == 1 {
+		// the pieces of the middle column: the fewest which leave
+		// a number of colors the halves can share
+		for m := 2 - colors%2; m <= colors && middle == nil; m += 2 {
+			if c.Symmetry == RotationalSymmetry {
+				middle = rotationalLengths(int(size), m, min, max, r)
+			} else if fits(int(size), m, min, max) {
+				middle = randomLengths(int(size), m, min, max, r)
+			}
+		}
+	} else if colors%2 != 0 {
+		return nil, fail(SymmetryConstraint)
+	}
+
+	pieces := (colors - len(middle)) / 2
+	if (size%2 == 1 && middle == nil) || !fits(int(half*size), pieces, min, max) {
+		return nil, fail(SymmetryConstraint)
+	}
+
+	p := newPartition(size, regionPath(size, half, r), randomLengths(int(half*size), pieces, min, max, r), min, max)
+	p.adj = regionNeighbours(size, half)
+
+	image := rotate180
+	if c.Symmetry == MirrorSymmetry {
+		image = mirror
+	}
+	p.image = func(sq int) int {
+		ic := image(Coordinate{int32(sq) / size, int32(sq) % size}, size, size)
+		return int(ic.X*size + ic.Y)
+	}
+
+	start := int(half * size)
+	for _, length := range middle {
+		var piece []int
+		for i := start; i < start+length; i++ {
+			piece = append(piece, i)
+		}
+		p.fixed = append(p.fixed, piece)
+		start += length
+	}
+
+	return p, nil
+}
+
+// rotationalLengths cuts the middle column of a board of odd size into
+// m pieces unchanged by a rotation by 180 degrees: a single piece, or three
+// pieces with the outer ones of the same length. It returns nil if there
+// is no such cut.
+func rotationalLengths(size, m, min, max int, r *rand.Rand) []int {
+	switch m {
+	case 1:
+		if fits(size, 1, min, max) {
+			return []int{size}
+		}
+	case 3:
+		var outer []int
+		for a := min; 2*a+min <= size; a++ {
+			if max == 0 || (a <= max && size-2*a <= max) {
+				outer = append(outer, a)
+			}
+		}
+		if len(outer) > 0 {
+			a := outer[r.Intn(len(outer))]
+			return []int{a, size - 2*a, a}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateConstraints(t *testing.T) {
+	l, err := Generate(7, 0, 1, WithUniqueSolution(), WithConstraints(Constraints{
+		MinPathLength:  5,
+		MaxPathLength:  9,
+		Colors:         7,
+		NoAdjacentDots: true,
+	}))
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	assert.Len(t, l.Dots, 14)
+
+	// the solution is unique: it is made of the generated paths
+	paths, err := SolveSAT(l)
+	assert.Nil(t, err)
+	for _, path := range paths {
+		assert.True(t, len(path.Lines) >= 4 && len(path.Lines) <= 8)
+
+		a, b := path.StartDot.Location, path.EndDot.Location
+		assert.NotEqual(t, 1, (a.X-b.X)*(a.X-b.X)+(a.Y-b.Y)*(a.Y-b.Y))
+	}
+
+	medium := Medium
+	l, err = Generate(6, 6, 1, WithUniqueSolution(), WithConstraints(Constraints{Difficulty: &medium}))
+	if assert.Nil(t, err) {
+		assert.Equal(t, Medium, l.Difficulty)
+	}
+}
+
+func TestGenerateSymmetry(t *testing.T) {
+	tests := []struct {
+		size     int32
+		colors   int
+		symmetry Symmetry
+		image    symmetry
+	}{
+		{6, 6, RotationalSymmetry, rotate180},
+		{7, 7, RotationalSymmetry, rotate180},
+		{6, 6, MirrorSymmetry, mirror},
+		{7, 6, MirrorSymmetry, mirror},
+	}
+
+	for _, test := range tests {
+		l, err := Generate(test.size, test.colors, 1, WithConstraints(Constraints{Symmetry: test.symmetry}))
+		if !assert.Nil(t, err) {
+			continue
+		}
+		assert.Len(t, l.Dots, 2*test.colors)
+
+		dots := make(map[Coordinate]bool)
+		for _, dot := range l.Dots {
+			dots[dot.Location] = true
+		}
+		for _, dot := range l.Dots {
+			assert.True(t, dots[test.image(dot.Location, l.Width, l.Height)])
+		}
+
+		paths, err := SolveSAT(l)
+		assert.Nil(t, err)
+		assert.Empty(t, Verify(l, paths))
+	}
+}
+
+func TestGenerateConstraintsFailed(t *testing.T) {
+	tests := []struct {
+		colors      int
+		constraints Constraints
+		failed      Constraint
+		attempts    int
+	}{
+		{5, Constraints{MinPathLength: 6}, PathLengthConstraint, 0},
+		{5, Constraints{MaxPathLength: 4}, PathLengthConstraint, 0},
+		{5, Constraints{Colors: 20}, ColorsConstraint, 0},
+		{4, Constraints{Symmetry: RotationalSymmetry}, SymmetryConstraint, 0},
+		{2, Constraints{Symmetry: MirrorSymmetry, MinPathLength: 5}, SymmetryConstraint, 0},
+	}
+
+	for _, test := range tests {
+		_, err := Generate(5, test.colors, 1, WithConstraints(test.constraints))
+		if ce, ok := err.(*ConstraintError); assert.True(t, ok) {
+			assert.Equal(t, test.failed, ce.Constraint)
+			assert.Equal(t, test.attempts, ce.Attempts)
+		}
+	}
+
+	expert := Expert
+	_, err := Generate(3, 3, 1, WithUniqueSolution(), WithConstraints(Constraints{Difficulty: &expert}))
+	if ce, ok := err.(*ConstraintError); assert.True(t, ok) {
+		assert.Equal(t, DifficultyConstraint, ce.Constraint)
+		assert.Equal(t, generateAttempts, ce.Attempts)
+	}
+}
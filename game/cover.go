@@ -0,0 +1,274 @@
+package game
+
+// cover is the state of a board shared by the solver and the deducer:
+// the squares covered by the paths, along with the flood fill of the free
+// regions checking the paths can still cover the board.
+//
+// Each path grows from both its dots (the solver only grows it from its
+// start dot): each pair has two trails whose tips are joined once
+// the path is complete.
+// The squares are indexed the same way the Board does: x*height+y.
+type cover struct {
+	// The height of the board (the number of rows).
+	height int32
+
+	// The pairs of dots to be connected.
+	pairs []pair
+
+	// The neighbours (orthogonal adjacent squares) of each square.
+	adj [][]int
+
+	// The pair index covering each square (or free).
+	grid []int
+
+	// The squares visited from each dot of a pair, starting with the dot.
+	// The last square of a trail is a tip of the path.
+	trails [][2][]int
+
+	// True for the paths whose tips were joined.
+	done []bool
+
+	// The number of the free squares.
+	free int
+
+	// Scratch buffers used by the feasibility checks.
+	region []int
+	stack  []int
+}
+
+func newCover(level *Level) (*cover, error) {
+	pairs, err := pairsOf(level)
+	if err != nil {
+		return nil, err
+	}
+
+	n := int(level.Width * level.Height)
+	c := &cover{
+		height: level.Height,
+		pairs:  pairs,
+		adj:    levelNeighbours(level),
+		grid:   make([]int, n),
+		trails: make([][2][]int, len(pairs)),
+		done:   make([]bool, len(pairs)),
+		free:   n - 2*len(pairs) - len(level.Blocked),
+		region: make([]int, n),
+		stack:  make([]int, 0, n),
+	}
+
+	for i := range c.grid {
+		c.grid[i] = free
+	}
+	for _, b := range level.Blocked {
+		c.grid[c.index(b)] = blockedSquare
+	}
+
+	for i, p := range pairs {
+		start, end := c.index(p.start.Location), c.index(p.end.Location)
+		c.grid[start] = i
+		c.grid[end] = i
+		c.trails[i] = [2][]int{{start}, {end}}
+	}
+
+	return c, nil
+}
+
+func (c *cover) index(co Coordinate) int {
+	return int(co.X*c.height + co.Y)
+}
+
+func (c *cover) coord(i int) Coordinate {
+	return Coordinate{int32(i) / c.height, int32(i) % c.height}
+}
+
+func (c *cover) adjacent(i, j int) bool {
+	for _, n := range c.adj[i] {
+		if n == j {
+			return true
+		}
+	}
+	return false
+}
+
+// tip returns the square a side of the path p ends at.
+func (c *cover) tip(p, side int) int {
+	t := c.trails[p][side]
+	return t[len(t)-1]
+}
+
+// tipAt checks if the square i is a tip of a path to be completed
+// and returns the pair and the side of the tip.
+func (c *cover) tipAt(i int) (int, int, bool) {
+	p := c.grid[i]
+	if p == free || c.done[p] {
+		return 0, 0, false
+	}
+	for side := 0; side < 2; side++ {
+		if c.tip(p, side) == i {
+			return p, side, true
+		}
+	}
+	return 0, 0, false
+}
+
+// solved checks if all the paths are complete and the board is covered.
+func (c *cover) solved() bool {
+	for _, done := range c.done {
+		if !done {
+			return false
+		}
+	}
+	return c.free == 0
+}
+
+// moves returns the squares a tip of the path p may be extended to:
+// the free neighbours and the other tip (if adjacent).
+func (c *cover) moves(p, side int, buf []int) []int {
+	buf = buf[:0]
+	other := c.tip(p, 1-side)
+	for _, n := range c.adj[c.tip(p, side)] {
+		if c.grid[n] == free || n == other {
+			buf = append(buf, n)
+		}
+	}
+	return buf
+}
+
+// extend moves a tip of the path p to the square n,
+// joining the path if n is its other tip.
+func (c *cover) extend(p, side, n int) {
+	if n == c.tip(p, 1-side) {
+		c.done[p] = true
+	} else {
+		c.grid[n] = p
+		c.free--
+	}
+	c.trails[p][side] = append(c.trails[p][side], n)
+}
+
+// retract undoes the last extend of a tip of the path p.
+func (c *cover) retract(p, side int) {
+	t := c.trails[p][side]
+	n := t[len(t)-1]
+	c.trails[p][side] = t[:len(t)-1]
+
+	if c.done[p] {
+		c.done[p] = false
+	} else {
+		c.grid[n] = free
+		c.free++
+	}
+}
+
+// exits counts the neighbours of the free square i a path can
+// come from: the free squares and the tips of the paths.
+func (c *cover) exits(i int) int {
+	exits := 0
+	for _, n := range c.adj[i] {
+		if c.grid[n] == free {
+			exits++
+		} else if _, _, ok := c.tipAt(n); ok {
+			exits++
+		}
+	}
+	return exits
+}
+
+// noDeadCells checks that every free square has at least two exits.
+func (c *cover) noDeadCells() bool {
+	for i, p := range c.grid {
+		if p == free && c.exits(i) < 2 {
+			return false
+		}
+	}
+	return true
+}
+
+// feasible prunes the states which cannot lead to a solution:
+// - a free square must have at least two neighbours a path can come from
+// - every region of free squares must be reachable by a path which
+// can enter and leave it
+// - the tips of every unfinished path must still be able to meet.
+func (c *cover) feasible() bool {
+	if !c.noDeadCells() {
+		return false
+	}
+
+	for i := range c.region {
+		c.region[i] = -1
+	}
+
+	var regions int
+	for i, p := range c.grid {
+		if p != free || c.region[i] >= 0 {
+			continue
+		}
+		c.fill(i, regions)
+		if !c.regionServed(regions) {
+			return false
+		}
+		regions++
+	}
+
+	for p := range c.pairs {
+		if !c.done[p] && !c.reachable(p) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fill labels the free region containing the square i.
+func (c *cover) fill(i, label int) {
+	c.stack = append(c.stack[:0], i)
+	c.region[i] = label
+	for len(c.stack) > 0 {
+		s := c.stack[len(c.stack)-1]
+		c.stack = c.stack[:len(c.stack)-1]
+		for _, n := range c.adj[s] {
+			if c.grid[n] == free && c.region[n] < 0 {
+				c.region[n] = label
+				c.stack = append(c.stack, n)
+			}
+		}
+	}
+}
+
+// touches checks if the square i is adjacent to the region.
+func (c *cover) touches(i, label int) bool {
+	for _, n := range c.adj[i] {
+		if c.grid[n] == free && c.region[n] == label {
+			return true
+		}
+	}
+	return false
+}
+
+// regionServed checks if some unfinished path has both its tips
+// adjacent to the region, so it can cover the region's squares.
+func (c *cover) regionServed(label int) bool {
+	for p := range c.pairs {
+		if c.done[p] {
+			continue
+		}
+		if c.touches(c.tip(p, 0), label) && c.touches(c.tip(p, 1), label) {
+			return true
+		}
+	}
+	return false
+}
+
+// reachable checks if the tips of the path p are adjacent
+// or share a free region.
+func (c *cover) reachable(p int) bool {
+	a, b := c.tip(p, 0), c.tip(p, 1)
+	for _, n := range c.adj[a] {
+		if n == b {
+			return true
+		}
+		if c.grid[n] == free && c.touches(b, c.region[n]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,272 @@
+package game
+
+import (
+	"connect-dots/graphics"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// dateLayout is the layout of the dates of the daily puzzles.
+const dateLayout = "2006-01-02"
+
+// dailySizes gives the size of the daily puzzle by the day of the week:
+// the puzzles get bigger as the week goes on (from Monday).
+var dailySizes = [7]int32{
+	time.Sunday:    9,
+	time.Monday:    5,
+	time.Tuesday:   6,
+	time.Wednesday: 6,
+	time.Thursday:  7,
+	time.Friday:    7,
+	time.Saturday:  8,
+}
+
+// DailySeed returns the seed of the daily puzzle of the given date.
+// Only the calendar date matters, so everyone gets the same seed
+// on the same day.
+func DailySeed(date time.Time) int64 {
+	y, m, d := date.Date()
+	return int64(y*10000 + int(m)*100 + d)
+}
+
+// Daily generates the daily puzzle of the given date: the same date
+// always gives the same level, having a unique solution.
+func Daily(date time.Time) (*Level, error) {
+	return DailyContext(context.Background(), date)
+}
+
+// DailyContext is Daily with a context: the generation is cancelled
+// with the context (in which case the error of the context is returned).
+func DailyContext(ctx context.Context, date time.Time) (*Level, error) {
+	size := dailySizes[date.Weekday()]
+	colors := int(size) - 1
+
+	r := rand.New(rand.NewSource(DailySeed(date)))
+
+	var err error
+	for i := 0; i < endlessAttempts; i++ {
+		var l *Level
+		l, err = GenerateContext(ctx, size, colors, r.Int63(), WithUniqueSolution())
+		if err == nil {
+			l.Name = "Daily puzzle " + date.Format(dateLayout)
+			return l, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	return nil, err
+}
+
+// Streak stores the daily puzzles solved in a row.
+type Streak struct {
+	// The date of the last daily puzzle solved (empty if none).
+	Last string `json:"last"`
+
+	// The number of consecutive days the daily puzzle was solved,
+	// up to the last one.
+	Count int32 `json:"count"`
+
+	// The longest streak so far.
+	Best int32 `json:"best"`
+}
+
+// DefaultStreakFile returns the path of the file storing the streak
+// in the user configuration directory.
+func DefaultStreakFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "connect-dots", "streak.json"), nil
+}
+
+// LoadStreak reads the streak from a file. A missing file gives
+// an empty streak.
+func LoadStreak(path string) (*Streak, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Streak{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var s Streak
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("Invalid streak file %s: %v", path, err)
+	}
+	return &s, nil
+}
+
+// Save writes the streak to a file (creating its directory if needed).
+func (s *Streak) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Record counts the daily puzzle of the given date as solved: the streak
+// goes on if the puzzle of the day before was solved, or starts over.
+// Solving the same puzzle again changes nothing.
+func (s *Streak) Record(date time.Time) {
+	day := date.Format(dateLayout)
+	if s.Last == day {
+		return
+	}
+
+	if s.Last == date.AddDate(0, 0, -1).Format(dateLayout) {
+		s.Count++
+	} else {
+		s.Count = 1
+	}
+	s.Last = day
+
+	if s.Count > s.Best {
+		s.Best = s.Count
+	}
+}
+
+// dailyPuzzle is the daily puzzle being played.
+type dailyPuzzle struct {
+	// The date of the puzzle.
+	date time.Time
+
+	// The level (and the window title) left for the daily puzzle.
+	left  *Level
+	title string
+}
+
+// pendingDaily is a daily puzzle being generated in the background,
+// so the game loop keeps pumping the SDL events meanwhile.
+type pendingDaily struct {
+	cancel context.CancelFunc
+
+	// Closed when the generation ends.
+	done chan struct{}
+
+	// The date of the puzzle and the renderer to play it with.
+	date time.Time
+	gr   *graphics.Renderer
+
+	// The result of the generation (read only after done is closed).
+	level *Level
+	err   error
+}
+
+// startDaily starts generating the daily puzzle of the given date
+// in the background.
+func startDaily(gr *graphics.Renderer, date time.Time) *pendingDaily {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &pendingDaily{
+		cancel: cancel,
+		done:   make(chan struct{}),
+		date:   date,
+		gr:     gr,
+	}
+
+	go func() {
+		defer close(d.done)
+		d.level, d.err = DailyContext(ctx, date)
+	}()
+
+	return d
+}
+
+// finished checks (without blocking) if the generation ended.
+func (d *pendingDaily) finished() bool {
+	select {
+	case <-d.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// stop cancels the generation and waits for its goroutine to end.
+func (d *pendingDaily) stop() {
+	d.cancel()
+	<-d.done
+}
+
+// PlayDaily leaves the current level for the daily puzzle of the given
+// date. The puzzle is generated in the background and played by Update
+// once it is ready. The game goes back to the level left once the player
+// continues.
+func (g *Game) PlayDaily(gr *graphics.Renderer, date time.Time) {
+	g.stopDaily()
+	g.pendingDaily = startDaily(gr, date)
+}
+
+// stopDaily cancels the generation of the daily puzzle (if any).
+func (g *Game) stopDaily() {
+	if g.pendingDaily != nil {
+		g.pendingDaily.stop()
+		g.pendingDaily = nil
+	}
+}
+
+// playDaily switches to the daily puzzle once its generation ends.
+func (g *Game) playDaily() {
+	d := g.pendingDaily
+	g.pendingDaily = nil
+
+	if d.err != nil {
+		g.log.Error("Failed to generate the daily puzzle", zap.Error(d.err))
+		return
+	}
+
+	g.stopSolve()
+
+	if g.daily == nil {
+		g.daily = &dailyPuzzle{left: g.level, title: g.title}
+	}
+	g.daily.date = d.date
+
+	day := d.date.Format(dateLayout)
+	g.log.Info("Daily puzzle", zap.String("date", day), zap.Int32("size", d.level.Width))
+	g.setTitle(fmt.Sprintf("dots connected - daily puzzle %s", day))
+
+	g.play(d.gr, d.level)
+}
+
+// leaveDaily ends the daily puzzle and returns the level left for it.
+func (g *Game) leaveDaily() *Level {
+	l := g.daily.left
+	g.setTitle(g.daily.title)
+	g.daily = nil
+	return l
+}
+
+// recordDaily adds the completed daily puzzle to the streak.
+func (g *Game) recordDaily() {
+	if g.daily == nil || g.streakFile == "" {
+		return
+	}
+
+	s, err := LoadStreak(g.streakFile)
+	if err != nil {
+		g.log.Error("Failed to load the streak", zap.Error(err))
+		return
+	}
+
+	s.Record(g.daily.date)
+	if err := s.Save(g.streakFile); err != nil {
+		g.log.Error("Failed to save the streak", zap.Error(err))
+	}
+	g.Streak = s.Count
+}
@@ -0,0 +1,91 @@
+package game
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDaily(t *testing.T) {
+	date := time.Date(2020, time.March, 3, 8, 0, 0, 0, time.UTC)
+
+	l, err := Daily(date)
+	assert.Nil(t, err)
+	assert.Equal(t, dailySizes[time.Tuesday], l.Width)
+	assert.Equal(t, dailySizes[time.Tuesday], l.Height)
+
+	// the same day gives the same puzzle at any time
+	again, err := Daily(date.Add(12 * time.Hour))
+	assert.Nil(t, err)
+	assert.Equal(t, l, again)
+
+	other, err := Daily(date.AddDate(0, 0, 7))
+	assert.Nil(t, err)
+	assert.NotEqual(t, l, other)
+}
+
+func TestPlayDaily(t *testing.T) {
+	date := time.Date(2020, time.March, 3, 8, 0, 0, 0, time.UTC)
+
+	// a board of the same size, so the grid is kept
+	l, err := Daily(date.AddDate(0, 0, 7))
+	assert.Nil(t, err)
+	g := newTestGame(l)
+	defer g.Close()
+
+	g.PlayDaily(nil, date)
+	assert.Equal(t, l, g.level)
+
+	deadline := time.Now().Add(10 * time.Second)
+	for g.pendingDaily != nil && time.Now().Before(deadline) {
+		g.Update()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if assert.Nil(t, g.pendingDaily) {
+		assert.Equal(t, "Daily puzzle 2020-03-03", g.level.Name)
+		assert.Equal(t, l, g.daily.left)
+	}
+}
+
+func TestStreak(t *testing.T) {
+	day := time.Date(2020, time.February, 28, 0, 0, 0, 0, time.UTC)
+
+	var s Streak
+	s.Record(day)
+	assert.Equal(t, Streak{Last: "2020-02-28", Count: 1, Best: 1}, s)
+
+	// solving the same puzzle again changes nothing
+	s.Record(day)
+	assert.Equal(t, int32(1), s.Count)
+
+	s.Record(day.AddDate(0, 0, 1))
+	s.Record(day.AddDate(0, 0, 2))
+	assert.Equal(t, Streak{Last: "2020-03-01", Count: 3, Best: 3}, s)
+
+	// a day missed
+	s.Record(day.AddDate(0, 0, 4))
+	assert.Equal(t, Streak{Last: "2020-03-03", Count: 1, Best: 3}, s)
+}
+
+func TestStreakFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "streak")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "connect-dots", "streak.json")
+	s, err := LoadStreak(path)
+	assert.Nil(t, err)
+	assert.Equal(t, &Streak{}, s)
+
+	s.Record(time.Date(2020, time.March, 3, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, s.Save(path))
+
+	loaded, err := LoadStreak(path)
+	assert.Nil(t, err)
+	assert.Equal(t, s, loaded)
+}
@@ -0,0 +1,71 @@
+package game
+
+// DeadEnds stores the problems of a board which cannot be fixed
+// without erasing some lines.
+type DeadEnds struct {
+	// The free squares which can no longer be covered by any path:
+	// the squares with less than two neighbours a path can come from
+	// and the regions no path can enter and leave.
+	Squares []Coordinate
+
+	// The dots of the pairs which can no longer be connected.
+	Dots []Dot
+}
+
+// Empty checks if no dead end was found.
+func (d *DeadEnds) Empty() bool {
+	return len(d.Squares) == 0 && len(d.Dots) == 0
+}
+
+// FindDeadEnds looks for the dead ends of a board where the player
+// may have drawn some paths. It only checks the current state of the
+// board (no search involved), so it is fast enough to run on every
+// line drawn.
+func FindDeadEnds(level *Level, b *Board) (*DeadEnds, error) {
+	d, err := newBoardDeducer(level, b)
+	if err != nil {
+		return nil, err
+	}
+
+	dead := make([]bool, len(d.grid))
+	for i, p := range d.grid {
+		if p == free && d.exits(i) < 2 {
+			dead[i] = true
+		}
+	}
+
+	for i := range d.region {
+		d.region[i] = -1
+	}
+
+	var regions int
+	for i, p := range d.grid {
+		if p != free || d.region[i] >= 0 {
+			continue
+		}
+		d.fill(i, regions)
+		if !d.regionServed(regions) {
+			for j, label := range d.region {
+				if label == regions {
+					dead[j] = true
+				}
+			}
+		}
+		regions++
+	}
+
+	de := &DeadEnds{}
+	for i := range dead {
+		if dead[i] {
+			de.Squares = append(de.Squares, d.coord(i))
+		}
+	}
+
+	for p, pr := range d.pairs {
+		if !d.done[p] && !d.reachable(p) {
+			de.Dots = append(de.Dots, pr.start, pr.end)
+		}
+	}
+
+	return de, nil
+}
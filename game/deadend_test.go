@@ -0,0 +1,50 @@
+package game
+
+import (
+	"connect-dots/graphics"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// drawPath draws a path from a dot through the given squares.
+func drawPath(b *Board, dot Dot, squares ...Coordinate) {
+	path := b.Paths[dot]
+	from := dot.Location
+	for _, to := range squares {
+		path.AddLine(from, to)
+		*b.ColorAt(to.X, to.Y) = dot.Color
+		from = to
+	}
+}
+
+func TestFindDeadEnds(t *testing.T) {
+	l := &Level{Width: 3, Height: 3, Dots: []Dot{
+		{Coordinate{0, 0}, graphics.Red},
+		{Coordinate{2, 0}, graphics.Red},
+		{Coordinate{0, 2}, graphics.Blue},
+		{Coordinate{2, 2}, graphics.Blue},
+	}}
+
+	b := NewBoard(l.Width, l.Height)
+	b.InitPaths(l.Dots)
+
+	de, err := FindDeadEnds(l, b)
+	assert.Nil(t, err)
+	assert.True(t, de.Empty())
+
+	// the red path goes around the square (1,0)
+	drawPath(b, l.Dots[0], Coordinate{0, 1}, Coordinate{1, 1}, Coordinate{2, 1}, Coordinate{2, 0})
+	de, err = FindDeadEnds(l, b)
+	assert.Nil(t, err)
+	assert.Equal(t, []Coordinate{{1, 0}}, de.Squares)
+	assert.Empty(t, de.Dots)
+
+	// the red path gets stuck between the blue dots and cuts them apart
+	b = NewBoard(l.Width, l.Height)
+	b.InitPaths(l.Dots)
+	drawPath(b, l.Dots[0], Coordinate{0, 1}, Coordinate{1, 1}, Coordinate{1, 2})
+	de, err = FindDeadEnds(l, b)
+	assert.Nil(t, err)
+	assert.Equal(t, l.Dots, de.Dots)
+}
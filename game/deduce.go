@@ -0,0 +1,280 @@
+package game
+
+import (
+	"connect-dots/graphics"
+	"fmt"
+)
+
+// Technique is a deduction technique a player uses to find the next move.
+// The techniques are listed from the easiest to the hardest one.
+type Technique int
+
+const (
+	// A path end has a single square it can be extended to.
+	ForcedMove Technique = iota
+	// A free square can only be reached from two sides, one of them
+	// being a path end (e.g. a corner next to a dot).
+	CornerRule
+	// All the moves of a path end but one leave a free square
+	// which cannot be covered anymore.
+	DeadCell
+	// All the moves of a path end but one cut off a pair of dots or
+	// a free region no path can cover.
+	Bottleneck
+)
+
+var techniqueNames = []string{
+	"forced move",
+	"corner rule",
+	"dead cell",
+	"bottleneck",
+}
+
+// String returns the name of the technique.
+func (t Technique) String() string {
+	if t >= 0 && int(t) < len(techniqueNames) {
+		return techniqueNames[t]
+	}
+	return "unknown technique"
+}
+
+// Deduction is a move found by a deduction technique: the path of
+// the given color is extended by a line.
+type Deduction struct {
+	// The technique which found the move.
+	Technique Technique
+
+	// The color of the path.
+	Color graphics.Color
+
+	// The path end the line starts from.
+	From Coordinate
+
+	// The square the line goes to.
+	To Coordinate
+
+	// The squares the technique looked at: the path end, the square of
+	// the move and, for the techniques ruling out moves, the squares of
+	// the moves ruled out.
+	Cells []Coordinate
+
+	// The pair, the path end (0 from the start dot, 1 from the end dot)
+	// and the square of the move.
+	pair, side, square int
+}
+
+// Reason explains why the move is forced.
+func (d *Deduction) Reason() string {
+	switch d.Technique {
+	case ForcedMove:
+		return fmt.Sprintf("Square (%d,%d) has only one way out, so the %s path must go on to (%d,%d)",
+			d.From.X, d.From.Y, d.Color, d.To.X, d.To.Y)
+	case CornerRule:
+		return fmt.Sprintf("Square (%d,%d) can only be reached from two sides, so the %s path at (%d,%d) must pass through it",
+			d.To.X, d.To.Y, d.Color, d.From.X, d.From.Y)
+	case DeadCell:
+		return fmt.Sprintf("Any other move of the %s path at (%d,%d) leaves a square no path can reach, so it must go on to (%d,%d)",
+			d.Color, d.From.X, d.From.Y, d.To.X, d.To.Y)
+	case Bottleneck:
+		return fmt.Sprintf("Any other move of the %s path at (%d,%d) cuts off a pair of dots or an empty region, so it must go on to (%d,%d)",
+			d.Color, d.From.X, d.From.Y, d.To.X, d.To.Y)
+	}
+	return fmt.Sprintf("The %s path at (%d,%d) must go on to (%d,%d)",
+		d.Color, d.From.X, d.From.Y, d.To.X, d.To.Y)
+}
+
+// deducer solves a level the way a player does: each move is found by
+// a deduction technique, no guessing involved.
+//
+// Unlike the solver, the paths grow from both their dots: each pair has
+// two tips which are joined once the path is complete.
+type deducer struct {
+	// The squares covered by the paths.
+	*cover
+
+	// The side each path may grow from (-1 for both sides): the paths
+	// drawn on a board only grow from their last square.
+	grows []int
+}
+
+func newDeducer(level *Level) (*deducer, error) {
+	c, err := newCover(level)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &deducer{cover: c, grows: make([]int, len(c.pairs))}
+	for i := range d.grows {
+		d.grows[i] = -1
+	}
+	return d, nil
+}
+
+// newBoardDeducer creates a deducer for a snapshot of a board
+// where the player may have drawn some paths.
+func newBoardDeducer(level *Level, b *Board) (*deducer, error) {
+	d, err := newDeducer(level)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range b.Paths {
+		if len(path.Lines) == 0 {
+			continue
+		}
+
+		start := d.index(path.StartDot.Location)
+		p, side := d.grid[start], -1
+		if p >= 0 && d.pairs[p].color == path.StartDot.Color {
+			for i := 0; i < 2; i++ {
+				if d.trails[p][i][0] == start {
+					side = i
+				}
+			}
+		}
+		if side < 0 {
+			return nil, fmt.Errorf("The path of color %s does not start from a dot", path.StartDot.Color)
+		}
+
+		if len(d.trails[p][side]) != 1 || d.grows[p] >= 0 {
+			return nil, fmt.Errorf("Several paths of color %s", path.StartDot.Color)
+		}
+		d.grows[p] = side
+
+		for _, l := range path.Lines {
+			from, to := d.index(l.From), d.index(l.To)
+			legal := from == d.tip(p, side) && !d.done[p] &&
+				(d.grid[to] == free || to == d.tip(p, 1-side)) && d.adjacent(from, to)
+			if !legal {
+				return nil, fmt.Errorf("Invalid line (%d,%d)-(%d,%d) of color %s",
+					l.From.X, l.From.Y, l.To.X, l.To.Y, path.StartDot.Color)
+			}
+			d.extend(p, side, to)
+		}
+	}
+
+	return d, nil
+}
+
+// growable checks if a side of the path p may be extended.
+func (d *deducer) growable(p, side int) bool {
+	return !d.done[p] && (d.grows[p] < 0 || d.grows[p] == side)
+}
+
+// apply plays a deduction.
+func (d *deducer) apply(ded Deduction) {
+	d.extend(ded.pair, ded.side, ded.square)
+}
+
+func (d *deducer) deduction(t Technique, p, side, n int) Deduction {
+	from, to := d.coord(d.tip(p, side)), d.coord(n)
+	return Deduction{
+		Technique: t,
+		Color:     d.pairs[p].color,
+		From:      from,
+		To:        to,
+		Cells:     []Coordinate{from, to},
+		pair:      p,
+		side:      side,
+		square:    n,
+	}
+}
+
+// next returns the move found by the easiest technique which applies
+// or false if none of them does.
+func (d *deducer) next() (Deduction, bool) {
+	if ded, ok := d.forcedMove(); ok {
+		return ded, true
+	}
+	if ded, ok := d.cornerRule(); ok {
+		return ded, true
+	}
+	if ded, ok := d.eliminate(DeadCell, d.noDeadCells); ok {
+		return ded, true
+	}
+	return d.eliminate(Bottleneck, d.feasible)
+}
+
+// forcedMove looks for a tip having a single move.
+func (d *deducer) forcedMove() (Deduction, bool) {
+	buf := make([]int, 0, 4)
+	for p := range d.pairs {
+		for side := 0; side < 2; side++ {
+			if !d.growable(p, side) {
+				continue
+			}
+			if m := d.moves(p, side, buf); len(m) == 1 {
+				return d.deduction(ForcedMove, p, side, m[0]), true
+			}
+		}
+	}
+	return Deduction{}, false
+}
+
+// cornerRule looks for a free square having two exits, one of them
+// being a tip: the square must be covered by the path of that tip.
+func (d *deducer) cornerRule() (Deduction, bool) {
+	for i, p := range d.grid {
+		if p != free || d.exits(i) != 2 {
+			continue
+		}
+		for _, n := range d.adj[i] {
+			if q, side, ok := d.tipAt(n); ok && d.growable(q, side) {
+				return d.deduction(CornerRule, q, side, i), true
+			}
+		}
+	}
+	return Deduction{}, false
+}
+
+// eliminate looks for a tip whose moves, except for one, lead to
+// a state rejected by the check.
+func (d *deducer) eliminate(t Technique, check func() bool) (Deduction, bool) {
+	buf := make([]int, 0, 4)
+	for p := range d.pairs {
+		for side := 0; side < 2; side++ {
+			if !d.growable(p, side) {
+				continue
+			}
+			moves := d.moves(p, side, buf)
+			if len(moves) < 2 {
+				continue
+			}
+
+			valid, last := 0, -1
+			var rejected []Coordinate
+			for _, n := range moves {
+				d.extend(p, side, n)
+				if check() {
+					valid++
+					last = n
+				} else {
+					rejected = append(rejected, d.coord(n))
+				}
+				d.retract(p, side)
+			}
+			if valid == 1 {
+				ded := d.deduction(t, p, side, last)
+				ded.Cells = append(ded.Cells, rejected...)
+				return ded, true
+			}
+		}
+	}
+	return Deduction{}, false
+}
+
+// Explain looks for the next move on a board (where the player may have
+// drawn some paths) which can be found by a deduction technique and
+// explains it. It returns nil if no technique applies.
+func Explain(level *Level, b *Board) (*Deduction, error) {
+	d, err := newBoardDeducer(level, b)
+	if err != nil {
+		return nil, err
+	}
+
+	ded, ok := d.next()
+	if !ok {
+		return nil, nil
+	}
+	return &ded, nil
+}
@@ -0,0 +1,50 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func mediumLevel(t *testing.T) *Level {
+	return levelOf(t, 5, `[{"x":1,"y":1,"color":"red"},{"x":0,"y":0,"color":"red"},{"x":0,"y":1,"color":"green"},
+		{"x":0,"y":2,"color":"green"},{"x":1,"y":4,"color":"blue"},{"x":4,"y":2,"color":"blue"},
+		{"x":3,"y":1,"color":"yellow"},{"x":4,"y":3,"color":"yellow"}]`)
+}
+
+func TestExplain(t *testing.T) {
+	l := mediumLevel(t)
+	b := NewBoard(l.Width, l.Height)
+	b.InitPaths(l.Dots)
+
+	ded, err := Explain(l, b)
+	assert.Nil(t, err)
+	if assert.NotNil(t, ded) {
+		assert.Equal(t, ForcedMove, ded.Technique)
+		assert.Equal(t, Coordinate{0, 0}, ded.From)
+		assert.Equal(t, Coordinate{1, 0}, ded.To)
+		assert.Equal(t, []Coordinate{{0, 0}, {1, 0}}, ded.Cells)
+		assert.Equal(t, "Square (0,0) has only one way out, so the red path must go on to (1,0)", ded.Reason())
+	}
+}
+
+func TestExplainDrawnPath(t *testing.T) {
+	l := mediumLevel(t)
+	b := NewBoard(l.Width, l.Height)
+	b.InitPaths(l.Dots)
+
+	// the red path drawn from (1,1) only grows from its last square
+	red := b.Paths[l.Dots[0]]
+	red.AddLine(Coordinate{1, 1}, Coordinate{1, 0})
+	*b.ColorAt(1, 0) = l.Dots[0].Color
+
+	ded, err := Explain(l, b)
+	assert.Nil(t, err)
+	if assert.NotNil(t, ded) {
+		assert.NotEqual(t, Coordinate{0, 0}, ded.From)
+	}
+
+	red.AddLine(Coordinate{1, 0}, Coordinate{3, 0})
+	_, err = Explain(l, b)
+	assert.NotNil(t, err)
+}
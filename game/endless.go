@@ -0,0 +1,123 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// endlessAttempts is the number of seeds tried for a generated
+// level before giving up.
+const endlessAttempts = 3
+
+// generated stores the parameters a generated level was created with,
+// so the level can be generated again.
+type generated struct {
+	Size   int32
+	Colors int
+	Seed   int64
+}
+
+// String returns the parameters as size:colors:seed, the form
+// given to Replay.
+func (g generated) String() string {
+	return fmt.Sprintf("%d:%d:%d", g.Size, g.Colors, g.Seed)
+}
+
+// parseGenerated reads the parameters of a generated level
+// given as size:colors:seed.
+func parseGenerated(spec string) (generated, error) {
+	s := strings.Split(spec, ":")
+	if len(s) != 3 {
+		return generated{}, fmt.Errorf("Invalid replay spec (expecting size:colors:seed): %s", spec)
+	}
+
+	size, err := strconv.ParseInt(s[0], 10, 32)
+	if err != nil {
+		return generated{}, fmt.Errorf("Invalid value for size: %s", s[0])
+	}
+	colors, err := strconv.Atoi(s[1])
+	if err != nil {
+		return generated{}, fmt.Errorf("Invalid value for colors: %s", s[1])
+	}
+	seed, err := strconv.ParseInt(s[2], 10, 64)
+	if err != nil {
+		return generated{}, fmt.Errorf("Invalid value for seed: %s", s[2])
+	}
+	return generated{int32(size), colors, seed}, nil
+}
+
+// Replay generates again a level of the endless mode from its
+// parameters given as size:colors:seed (as logged by the game).
+func Replay(spec string) (*Level, error) {
+	g, err := parseGenerated(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := Generate(g.Size, g.Colors, g.Seed, WithUniqueSolution())
+	if err != nil {
+		return nil, err
+	}
+	l.Name = "Replay " + spec
+	return l, nil
+}
+
+// endless is the endless mode the game switches to once the level files
+// run out: the levels are generated, getting bigger and more colorful
+// as the player moves on.
+type endless struct {
+	// The source of the seeds of the generated levels.
+	rand *rand.Rand
+
+	// The levels generated so far (the last one is the current level).
+	levels []generated
+}
+
+func newEndless(seed int64) *endless {
+	return &endless{rand: rand.New(rand.NewSource(seed))}
+}
+
+// ramp returns the size and the number of colors of the k-th generated
+// level: the size grows by one every three levels, up to maxBoardSize,
+// and the number of colors grows along with it.
+func ramp(k int) (int32, int) {
+	size := int32(5 + k/3)
+	if size > maxBoardSize {
+		size = maxBoardSize
+	}
+
+	colors := int(size) - 1 + k%3
+	if colors > len(palette) {
+		colors = len(palette)
+	}
+	return size, colors
+}
+
+// next generates the next level.
+func (e *endless) next(ctx context.Context) (*Level, error) {
+	size, colors := ramp(len(e.levels))
+
+	var err error
+	for i := 0; i < endlessAttempts; i++ {
+		seed := e.rand.Int63()
+
+		var l *Level
+		l, err = GenerateContext(ctx, size, colors, seed, WithUniqueSolution())
+		if err == nil {
+			e.levels = append(e.levels, generated{size, colors, seed})
+			return l, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+	}
+	return nil, err
+}
+
+// current returns the parameters of the current level.
+func (e *endless) current() generated {
+	return e.levels[len(e.levels)-1]
+}
@@ -0,0 +1,66 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRamp(t *testing.T) {
+	size, colors := ramp(0)
+	assert.Equal(t, int32(5), size)
+	assert.Equal(t, 4, colors)
+
+	size, colors = ramp(5)
+	assert.Equal(t, int32(6), size)
+	assert.Equal(t, 7, colors)
+
+	size, colors = ramp(1001)
+	assert.Equal(t, int32(maxBoardSize), size)
+	assert.Equal(t, maxBoardSize+1, colors)
+}
+
+func TestEndless(t *testing.T) {
+	e := newEndless(42)
+	for i := 0; i < 3; i++ {
+		l, err := e.next(context.Background())
+		assert.Nil(t, err)
+
+		// the recorded seed generates the same level again
+		cur := e.current()
+		again, err := Generate(cur.Size, cur.Colors, cur.Seed, WithUniqueSolution())
+		assert.Nil(t, err)
+		assert.Equal(t, l, again)
+	}
+	assert.Len(t, e.levels, 3)
+
+	// the same seed gives the same levels
+	other := newEndless(42)
+	for i := 0; i < 3; i++ {
+		_, err := other.next(context.Background())
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, e.levels, other.levels)
+}
+
+func TestReplay(t *testing.T) {
+	e := newEndless(42)
+	l, err := e.next(context.Background())
+	assert.Nil(t, err)
+
+	cur := e.current()
+	g, err := parseGenerated(cur.String())
+	assert.Nil(t, err)
+	assert.Equal(t, cur, g)
+
+	again, err := Replay(cur.String())
+	assert.Nil(t, err)
+	assert.Equal(t, l.Dots, again.Dots)
+	assert.Equal(t, "Replay "+cur.String(), again.Name)
+
+	for _, spec := range []string{"", "5:4", "5:4:x", "a:4:1", "5:4:1:2"} {
+		_, err := Replay(spec)
+		assert.Error(t, err, spec)
+	}
+}
@@ -5,11 +5,9 @@ import (
 	"connect-dots/graphics"
 	"connect-dots/ui"
 	"fmt"
-	"strconv"
 	"strings"
+	"time"
 
-	"log"
-	"math"
 	"os"
 
 	"github.com/veandco/go-sdl2/sdl"
@@ -42,9 +40,14 @@ type editPathState struct {
 	color graphics.Color
 	// the square of the board that the mouse in hovering over
 	square Coordinate
+
+	// the number of lines of the path (revealed by hints) which are kept
+	// if the path is not completed
+	keep int
 }
 
 func (s *editPathState) reset() {
+	s.keep = 0
 	s.editingPath = false
 	s.srcDot = nil
 	s.dstDot = nil
@@ -70,7 +73,13 @@ type Game struct {
 
 	//
 	movesText    *graphics.Text
+	levelText    *graphics.Text
 	coverageText *graphics.Text
+	solverText   *graphics.Text
+	hintButton   *graphics.Button
+
+	// The panel explaining the last hint.
+	explanationText *graphics.Text
 
 	// The current level.
 	level *Level
@@ -96,9 +105,81 @@ type Game struct {
 	// The overall attempts to successfully connect the dots.
 	Moves int32
 
+	// The number of hints used.
+	Hints int32
+
+	// The number of lines drawn by the assist mode.
+	AutoMoves int32
+
+	// The daily puzzle streak (the number of consecutive days the daily
+	// puzzle was solved), set when the daily puzzle gets completed.
+	Streak int32
+
 	// The board coverage (the number of the squares which are covered
 	// with dots or lines).
 	coverage int32
+
+	// The solve of the current level running in the background (if any).
+	solve *backgroundSolve
+
+	// The solution of the current level (once the background solve ends).
+	solution []*Path
+
+	// Whether the background solve ended with an error (logged once).
+	solveFailed bool
+
+	// The hint solve running in the background (if any)
+	// and the kind of the hint asked for.
+	hint     *backgroundSolve
+	hintKind hintKind
+
+	// The lines revealed by the hints.
+	hinted map[Line]bool
+
+	// The path drawn by the player which does not fit any solution (if any).
+	offending *Path
+
+	// The message displayed instead of the solver status (if any).
+	message string
+
+	// The move found by the deduction techniques when
+	// the hint was asked for (if any).
+	deduction *Deduction
+
+	// The explanation of the last hint and the squares it refers to.
+	explanation string
+	explained   []Coordinate
+
+	// The dead ends of the board (updated whenever a line is drawn or erased).
+	deadEnds *DeadEnds
+
+	// True if the assist mode (drawing the forced moves) is on.
+	assist bool
+
+	// The lines drawn by the assist mode after the last move (if any).
+	assisted *assistBatch
+
+	// The seed of the endless mode.
+	seed int64
+
+	// The levels generated so far in the endless mode.
+	generated []generated
+
+	// The producer of the upcoming levels (started along with
+	// the first level file).
+	prefetch *prefetch
+
+	// The daily puzzle being played (if any).
+	daily *dailyPuzzle
+
+	// The daily puzzle being generated (if any).
+	pendingDaily *pendingDaily
+
+	// The file storing the daily puzzle streak.
+	streakFile string
+
+	// The title of the game window.
+	title string
 }
 
 func newState() *editPathState {
@@ -123,20 +204,33 @@ func New(cfg *config.Config, assets *graphics.AssetsStorage, opts ...option) *Ga
 		window:       nil,
 		movesText:    nil,
 		coverageText: nil,
+		solverText:   nil,
+		hintButton:   nil,
 		level:        nil,
-		board:        NewBoard(cfg.Size),
+		board:        NewBoard(cfg.Width, cfg.Height),
 		dotBounds:    make(map[Dot]sdl.Rect),
 		lineBounds:   make(map[Line]sdl.Rect),
 		state:        newState(),
 		log:          zap.NewNop(),
 		Moves:        0,
+		Hints:        0,
 		coverage:     0,
+		hinted:       make(map[Line]bool),
+		seed:         time.Now().UnixNano(),
+		title:        "dots connected",
 	}
 
 	for _, opt := range opts {
 		opt(g)
 	}
 
+	if g.level != nil {
+		g.solveLevel()
+		if g.file != "" {
+			g.startPrefetch()
+		}
+	}
+
 	return g
 }
 
@@ -161,6 +255,41 @@ func WithCoverageText(text *graphics.Text) option {
 	}
 }
 
+//
+func WithSolverText(text *graphics.Text) option {
+	return func(g *Game) {
+		g.solverText = text
+	}
+}
+
+//
+func WithHintButton(button *graphics.Button) option {
+	return func(g *Game) {
+		g.hintButton = button
+	}
+}
+
+// WithAssist creates a game and turns the assist mode on or off.
+func WithAssist(on bool) option {
+	return func(g *Game) {
+		g.assist = on
+	}
+}
+
+//
+func WithLevelText(text *graphics.Text) option {
+	return func(g *Game) {
+		g.levelText = text
+	}
+}
+
+//
+func WithExplanationText(text *graphics.Text) option {
+	return func(g *Game) {
+		g.explanationText = text
+	}
+}
+
 // WithLogger creates a game and sets the logger.
 func WithLogger(log *zap.Logger) option { //nolint
 	return func(g *Game) {
@@ -171,6 +300,7 @@ func WithLogger(log *zap.Logger) option { //nolint
 // WithLevel creates a game and sets the level.
 func WithLevel(l *Level) option { //nolint
 	return func(g *Game) {
+		g.board.SetShape(l.Blocked, l.Walls)
 		for _, dot := range l.Dots {
 			d := Dot{
 				Location: dot.Location,
@@ -192,6 +322,22 @@ func WithLevel(l *Level) option { //nolint
 	}
 }
 
+// WithSeed creates a game and sets the seed of the levels
+// generated in the endless mode.
+func WithSeed(seed int64) option { //nolint
+	return func(g *Game) {
+		g.seed = seed
+	}
+}
+
+// WithStreakFile creates a game and sets the file storing
+// the daily puzzle streak.
+func WithStreakFile(path string) option { //nolint
+	return func(g *Game) {
+		g.streakFile = path
+	}
+}
+
 // WithFile creates a game and sets the name of the file
 // where the level was loaded from.
 func WithFile(file string) option { //nolint
@@ -206,68 +352,150 @@ func (g *Game) Repeat() {
 		delete(g.lineBounds, k)
 	}
 
+	g.stopHint()
+
 	g.Completed = false
 	g.Moves = 0
+	g.Hints = 0
+	g.AutoMoves = 0
+	g.assisted = nil
 	g.coverage = int32(len(g.dotBounds))
 
 	g.board.Clear()
 	g.board.InitPaths(g.level.Dots)
 
 	g.state.reset()
+	g.updateDeadEnds()
 }
 
-// Continue tries to move on to the next level.
-// It also triggers the creation of a new grid graphics asset
-// if the size of the board has changed.
-func (g *Game) Continue(gr *graphics.Renderer) {
-	s := strings.Split(g.file, ".")
-	if len(s) != 2 {
-		g.log.Fatal("Wrong file name for the game level", zap.String("file name", g.file))
+// solveLevel starts solving the current level in the background
+// (cancelling the previous solve if any).
+func (g *Game) solveLevel() {
+	g.stopSolve()
+	g.solve = startSolve(g.level, nil)
+}
+
+// stopSolve cancels the background solves (if any)
+// and forgets the solution of the level.
+func (g *Game) stopSolve() {
+	if g.solve != nil {
+		g.solve.stop()
+		g.solve = nil
 	}
+	g.solution = nil
+	g.solveFailed = false
+	g.stopHint()
+}
 
-	next, err := strconv.Atoi(s[0])
-	if err != nil {
-		g.log.Fatal("Internal error", zap.Error(err))
+// stopHint cancels the hint solve (if any) and forgets
+// the lines revealed and the messages of the previous hints.
+func (g *Game) stopHint() {
+	g.cancelHint()
+
+	for k := range g.hinted {
+		delete(g.hinted, k)
 	}
-	next++
+	g.deduction = nil
+}
 
-	dir, err := os.Getwd()
-	if err != nil {
-		log.Fatal("Failed to get the current working directory", zap.Error(err))
+// cancelHint cancels the hint solve (if any) and clears the messages
+// of the previous hint: the board changes, so they get outdated.
+func (g *Game) cancelHint() {
+	if g.hint != nil {
+		g.hint.stop()
+		g.hint = nil
 	}
 
-	nextSize := g.board.size
+	g.offending = nil
+	g.message = ""
+	g.explanation = ""
+	g.explained = nil
+}
+
+// Update checks (without blocking) if the background solves have ended.
+// It is called once per frame by the game loop.
+func (g *Game) Update() {
+	if g.pendingDaily != nil && g.pendingDaily.finished() {
+		g.playDaily()
+	}
 
-	var filePath string
-	for {
-		filePath = fmt.Sprintf("%s/data/%d/%d.json", dir, nextSize, next)
-		g.log.Debug("Try to load the level from", zap.String("file path", filePath))
+	if g.hint != nil && g.hint.finished() {
+		g.applyHint()
+	}
 
-		_, err := os.Stat(filePath)
-		if os.IsNotExist(err) {
-			if nextSize >= maxBoardSize {
-				g.log.Info("No more files to load the levels from")
-				ui.GameOver(g.window) //nolint
-				os.Exit(0)
-			}
-			nextSize++
-			next = 0
-			continue
-		}
-		break
+	if g.solve == nil || g.solution != nil || g.solveFailed || !g.solve.finished() {
+		return
+	}
+
+	if g.solve.err != nil {
+		g.log.Info("Failed to solve the level",
+			zap.String("file", g.file), zap.Error(g.solve.err))
+		g.solveFailed = true
+		return
 	}
-	g.file = fmt.Sprintf("%d.json", next)
 
-	g.log.Debug("Level file", zap.String("path", filePath))
+	g.solution = g.solve.paths
+	g.log.Debug("Level solved",
+		zap.String("file", g.file),
+		zap.Int64("nodes", g.solve.progress().Nodes))
+}
+
+// Close releases the resources of the game
+// (it cancels the background solve, the preparation
+// of the upcoming levels and the generation of the daily puzzle).
+func (g *Game) Close() {
+	g.stopDaily()
+	g.stopSolve()
+	g.stopPrefetch()
+
+	if len(g.generated) > 0 {
+		replay := make([]string, len(g.generated))
+		for i, gen := range g.generated {
+			replay[i] = gen.String()
+		}
+		g.log.Info("Levels generated", zap.Strings("replay", replay))
+	}
+}
 
-	l, err := LoadFromFile(filePath)
+// Replay leaves the current level for a level of the endless mode
+// generated again from its parameters given as size:colors:seed.
+func (g *Game) Replay(gr *graphics.Renderer, spec string) error {
+	l, err := Replay(spec)
 	if err != nil {
-		g.log.Fatal("Failed to load the level from file",
-			zap.String("file path", filePath),
-			zap.Error(err),
-		)
+		return err
+	}
+
+	g.stopSolve()
+	g.log.Info("Replay", zap.String("replay", spec))
+	g.setTitle("dots connected - replay " + spec)
+
+	g.play(gr, l)
+	return nil
+}
+
+// Continue tries to move on to the next level.
+// Once the level files run out, the game switches to the endless mode
+// where the levels are generated.
+func (g *Game) Continue(gr *graphics.Renderer) {
+	// the player leaves the level
+	g.stopSolve()
+
+	var l *Level
+	if g.daily != nil {
+		// back to the level left for the daily puzzle
+		l = g.leaveDaily()
+	}
+	if l == nil {
+		l = g.nextLevel()
 	}
 
+	g.play(gr, l)
+}
+
+// play replaces the current level with the given one.
+// It also triggers the creation of a new grid graphics asset
+// if the dimensions of the board have changed.
+func (g *Game) play(gr *graphics.Renderer, l *Level) {
 	for line := range g.lineBounds {
 		delete(g.lineBounds, line)
 	}
@@ -277,30 +505,153 @@ func (g *Game) Continue(gr *graphics.Renderer) {
 	}
 
 	g.state.reset()
+	prev := g.level
 	g.level = nil
 
 	g.board.Clear()
 	g.board = nil
-	g.board = NewBoard(l.Size)
+	g.board = NewBoard(l.Width, l.Height)
 
 	g.Completed = false
 	g.Moves = 0
+	g.Hints = 0
+	g.AutoMoves = 0
+	g.Streak = 0
+	g.assisted = nil
 	g.coverage = int32(len(g.dotBounds))
+	g.deadEnds = nil
 
-	if g.config.Size != g.board.size {
-		g.config.Size = g.board.size
+	if prev == nil || !prev.SameShape(l) {
+		BoardConfig(l)(g.config)
 		g.assets.Grid.Destroy()
 		g.assets.Grid = nil
 		g.assets.Grid = graphics.CreateGrid(gr, g.config)
 	}
+	g.assets.Prepare(gr, g.config, l.Colors())
 
 	WithLevel(l)(g)
+	g.solveLevel()
+}
+
+// BoardConfig returns the configuration option giving the board
+// the dimensions and the shape of a level.
+func BoardConfig(l *Level) config.Option {
+	return func(c *config.Config) {
+		c.Width, c.Height = l.Width, l.Height
+
+		c.Blocked = nil
+		for _, b := range l.Blocked {
+			c.Blocked = append(c.Blocked, sdl.Point{X: b.X, Y: b.Y})
+		}
+
+		c.Walls = nil
+		for _, w := range l.Walls {
+			c.Walls = append(c.Walls, [2]sdl.Point{{X: w.From.X, Y: w.From.Y}, {X: w.To.X, Y: w.To.Y}})
+		}
+	}
+}
+
+// startPrefetch starts preparing the levels following the current
+// level file in the background.
+func (g *Game) startPrefetch() {
+	dir, err := os.Getwd()
+	if err != nil {
+		g.log.Fatal("Failed to get the current working directory", zap.Error(err))
+	}
+
+	src, err := newLevelSource(dir, g.file, g.level.Width, g.seed)
+	if err != nil {
+		g.log.Fatal("Failed to find the levels to play next", zap.Error(err))
+	}
+	g.prefetch = startPrefetch(src)
+}
+
+// stopPrefetch cancels the preparation of the upcoming levels (if any).
+func (g *Game) stopPrefetch() {
+	if g.prefetch != nil {
+		g.prefetch.stop()
+		g.prefetch = nil
+	}
+}
+
+// nextLevel takes the next level from the queue of the upcoming levels:
+// the next level file, or a generated level once the level files run out.
+// The seeds of the generated levels are recorded so the levels can be
+// generated again.
+func (g *Game) nextLevel() *Level {
+	if g.prefetch == nil {
+		g.startPrefetch()
+	}
+
+	u := g.prefetch.next()
+	if u.err != nil {
+		g.log.Error("Failed to prepare the next level", zap.Error(u.err))
+		ui.GameOver(g.window) //nolint
+		os.Exit(0)
+	}
+	g.file = u.file
+
+	if u.generated == nil {
+		g.log.Debug("Level file", zap.String("file", u.file))
+		return u.level
+	}
+
+	if len(g.generated) == 0 {
+		g.log.Info("Switching to the endless mode", zap.Int64("seed", g.seed))
+	}
+	g.generated = append(g.generated, *u.generated)
+	g.log.Info("Level generated",
+		zap.Int32("size", u.generated.Size),
+		zap.Int("colors", u.generated.Colors),
+		zap.Int64("seed", u.generated.Seed),
+		zap.String("replay", u.generated.String()))
+
+	g.setTitle(fmt.Sprintf("dots connected - endless #%d (seed %d)",
+		len(g.generated), u.generated.Seed))
+	return u.level
+}
+
+// LevelName returns the name of the current level (made of the directory
+// and the file of the level if the level has no name), along with its
+// author if known.
+func (g *Game) LevelName() string {
+	if g.level == nil {
+		return ""
+	}
+
+	name := g.level.Name
+	if name == "" {
+		name = fmt.Sprintf("Level %d/%s", g.level.Width, strings.TrimSuffix(g.file, ".json"))
+	}
+	if g.level.Author != "" {
+		name += " by " + g.level.Author
+	}
+	return name
+}
+
+// Par returns the par of the current level (0 if unknown).
+func (g *Game) Par() int32 {
+	if g.level == nil {
+		return 0
+	}
+	return g.level.Par
+}
+
+// setTitle sets the title of the game window.
+func (g *Game) setTitle(title string) {
+	g.title = title
+	if g.window != nil {
+		g.window.SetTitle(title)
+	}
 }
 
 // Draw renders all the graphics objects on a rendering target.
 func (g *Game) Draw(r *graphics.Renderer) {
 	if g.movesText != nil {
-		g.movesText.Text = fmt.Sprintf("Moves %d", g.Moves)
+		g.movesText.Text = fmt.Sprintf("Moves %d, hints %d", g.Moves, g.Hints)
+		if g.assist {
+			g.movesText.Text = fmt.Sprintf("Moves %d, auto %d, hints %d", g.Moves, g.AutoMoves, g.Hints)
+		}
 		err := g.movesText.Draw(r, sdl.Point{X: 0, Y: 0})
 		if err != nil {
 			g.log.Fatal("Draw text (moves) failed", zap.Error(err))
@@ -309,7 +660,7 @@ func (g *Game) Draw(r *graphics.Renderer) {
 
 	if g.coverageText != nil {
 		c := g.coverage - int32(len(g.dotBounds))
-		sz := (g.board.size * g.board.size) - int32(len(g.dotBounds))
+		sz := g.board.Squares() - int32(len(g.dotBounds))
 		pc := int(float64(c) / float64(sz) * 100.0)
 		g.coverageText.Text = fmt.Sprintf("Coverage: %d %%", pc)
 		err := g.coverageText.Draw(r, sdl.Point{X: 0, Y: 40})
@@ -318,6 +669,34 @@ func (g *Game) Draw(r *graphics.Renderer) {
 		}
 	}
 
+	if g.solverText != nil {
+		if text := g.solverStatus(); text != "" {
+			g.solverText.Text = text
+			err := g.solverText.Draw(r, sdl.Point{X: 0, Y: 80})
+			if err != nil {
+				g.log.Fatal("Draw text (solver) failed", zap.Error(err))
+			}
+		}
+	}
+
+	if g.hintButton != nil {
+		err := g.hintButton.Draw(r, sdl.Point{X: 0, Y: 120})
+		if err != nil {
+			g.log.Fatal("Draw button (hint) failed", zap.Error(err))
+		}
+	}
+
+	if g.levelText != nil && g.level != nil {
+		g.levelText.Text = g.LevelName()
+		if g.level.Par > 0 {
+			g.levelText.Text += fmt.Sprintf(", par %d", g.level.Par)
+		}
+		err := g.levelText.Draw(r, sdl.Point{X: 0, Y: 170})
+		if err != nil {
+			g.log.Fatal("Draw text (level) failed", zap.Error(err))
+		}
+	}
+
 	g.assets.Grid.Blit(r)
 
 	for dot, rc := range g.dotBounds {
@@ -327,13 +706,133 @@ func (g *Game) Draw(r *graphics.Renderer) {
 
 	for line, rc := range g.lineBounds {
 		var l graphics.Renderable
-		if line.From.X == line.To.X {
+		switch {
+		case line.From.X == line.To.X && g.hinted[line]:
+			l = g.assets.HintVertLines[line.Color]
+		case line.From.X == line.To.X:
 			l = g.assets.VertLines[line.Color]
-		} else {
+		case g.hinted[line]:
+			l = g.assets.HintHorizLines[line.Color]
+		default:
 			l = g.assets.HorizLines[line.Color]
 		}
 		l.BlitTo(r, &rc)
 	}
+
+	if g.deadEnds != nil {
+		g.drawDeadEnds(r)
+	}
+
+	if g.offending != nil {
+		g.drawOffending(r)
+	}
+
+	if g.explanation != "" {
+		g.drawExplanation(r)
+	}
+}
+
+// drawOffending frames the squares of the path which
+// does not fit any solution.
+func (g *Game) drawOffending(r *graphics.Renderer) {
+	squares := []Coordinate{g.offending.StartDot.Location}
+	for _, l := range g.offending.Lines {
+		squares = append(squares, l.To)
+	}
+
+	r.SetDrawColor(255, 0, 0, 255)
+	g.frameSquares(r, squares)
+}
+
+// drawDeadEnds shades the squares which can no longer be covered
+// and frames the dots which can no longer be connected.
+func (g *Game) drawDeadEnds(r *graphics.Renderer) {
+	r.SetDrawColor(255, 0, 0, 90)
+	for _, c := range g.deadEnds.Squares {
+		rc := sdl.Rect{
+			X: g.assets.Grid.Bounds().X + c.X*g.config.SquareSize + 1,
+			Y: g.assets.Grid.Bounds().Y + c.Y*g.config.SquareSize + 1,
+			W: g.config.SquareSize - 2,
+			H: g.config.SquareSize - 2,
+		}
+		r.FillRect(&rc)
+	}
+
+	dots := make([]Coordinate, 0, len(g.deadEnds.Dots))
+	for _, dot := range g.deadEnds.Dots {
+		dots = append(dots, dot.Location)
+	}
+	r.SetDrawColor(255, 128, 0, 255)
+	g.frameSquares(r, dots)
+}
+
+// drawExplanation displays the explanation of the last hint
+// (at the bottom of the window) and frames the squares it refers to.
+func (g *Game) drawExplanation(r *graphics.Renderer) {
+	r.SetDrawColor(255, 255, 255, 255)
+	g.frameSquares(r, g.explained)
+
+	if g.explanationText == nil {
+		return
+	}
+
+	const lineHeight = 20
+	lines := graphics.Wrap(g.explanation, 80)
+	y := g.config.WindowHeight - int32(len(lines))*lineHeight - 10
+	for _, line := range lines {
+		g.explanationText.Text = line
+		err := g.explanationText.Draw(r, sdl.Point{X: 10, Y: y})
+		if err != nil {
+			g.log.Fatal("Draw text (explanation) failed", zap.Error(err))
+		}
+		y += lineHeight
+	}
+}
+
+// frameSquares draws a frame (with the current draw color)
+// inside each of the given squares.
+func (g *Game) frameSquares(r *graphics.Renderer, squares []Coordinate) {
+	for _, c := range squares {
+		rc := sdl.Rect{
+			X: g.assets.Grid.Bounds().X + c.X*g.config.SquareSize + 2,
+			Y: g.assets.Grid.Bounds().Y + c.Y*g.config.SquareSize + 2,
+			W: g.config.SquareSize - 4,
+			H: g.config.SquareSize - 4,
+		}
+		r.DrawRect(&rc)
+	}
+}
+
+// solverStatus returns the text describing the state
+// of the background solve.
+func (g *Game) solverStatus() string {
+	const spinner = `|/-\`
+	frame := spinner[(sdl.GetTicks()/100)%uint32(len(spinner))]
+
+	if g.message != "" {
+		return g.message
+	}
+
+	if g.hint != nil {
+		return fmt.Sprintf("Thinking %c %d nodes", frame, g.hint.progress().Nodes)
+	}
+
+	if g.solve == nil {
+		return ""
+	}
+
+	if !g.solve.finished() {
+		p := g.solve.progress()
+		return fmt.Sprintf("Solving %c %d nodes, depth %d", frame, p.Nodes, p.Depth)
+	}
+
+	switch g.solve.err {
+	case nil:
+		return "Solvable"
+	case ErrNoSolution:
+		return "No solution"
+	}
+	return ""
 }
 
 // MouseButtonDown handles the mouse button down events.
@@ -342,6 +841,13 @@ func (g *Game) MouseButtonDown(ev *sdl.MouseButtonEvent) {
 		return
 	}
 
+	if g.hintButton != nil && g.hintButton.ClickedInside(ev.X, ev.Y) {
+		g.HintSegment()
+		return
+	}
+
+	g.cancelHint()
+
 	var ok bool
 	grid, ok := g.assets.Grid.(*graphics.Grid)
 	if !ok {
@@ -358,6 +864,10 @@ func (g *Game) MouseButtonDown(ev *sdl.MouseButtonEvent) {
 		return
 	}
 
+	// the player edits the board: the lines drawn by the assist mode
+	// cannot be undone anymore
+	g.assisted = nil
+
 	c := Coordinate{cx, cy}
 	dot := Dot{
 		Location: c,
@@ -367,9 +877,12 @@ func (g *Game) MouseButtonDown(ev *sdl.MouseButtonEvent) {
 	var path *Path
 	path, ok = g.board.Paths[dot]
 	if !ok {
+		g.continuePath(c, clr)
 		return
 	}
 
+	g.erasePartialPaths(clr)
+
 	if path.EndDot != nil && len(path.Lines) == 0 {
 		path, ok = g.board.Paths[*path.EndDot]
 		if !ok {
@@ -392,7 +905,7 @@ func (g *Game) MouseButtonDown(ev *sdl.MouseButtonEvent) {
 				To:    line.To,
 				Color: clr,
 			}
-			delete(g.lineBounds, l)
+			g.deleteLine(l)
 		}
 
 		endDot := *path.EndDot
@@ -405,6 +918,7 @@ func (g *Game) MouseButtonDown(ev *sdl.MouseButtonEvent) {
 			p.EndDot = nil
 			p.Lines = nil
 		}
+		g.updateDeadEnds()
 	}
 
 	g.state.srcDot = &dot
@@ -415,6 +929,64 @@ func (g *Game) MouseButtonDown(ev *sdl.MouseButtonEvent) {
 	g.state.editingPath = true
 }
 
+// continuePath starts editing a path revealed partially by the hints
+// from its last square.
+func (g *Game) continuePath(c Coordinate, clr graphics.Color) {
+	for _, path := range g.board.Paths {
+		n := len(path.Lines)
+		if path.EndDot != nil || n == 0 || path.StartDot.Color != clr || path.Lines[n-1].To != c {
+			continue
+		}
+
+		src := *path.StartDot
+		g.state.srcDot = &src
+		g.state.path = path
+		g.state.dstDot = nil
+		g.state.color = clr
+		g.state.square = c
+		g.state.keep = n
+		g.state.editingPath = true
+		return
+	}
+}
+
+// erasePartialPaths removes the paths of a color which
+// were revealed partially by the hints.
+func (g *Game) erasePartialPaths(clr graphics.Color) {
+	for _, path := range g.board.Paths {
+		if path.EndDot != nil || len(path.Lines) == 0 || path.StartDot.Color != clr {
+			continue
+		}
+
+		for _, line := range path.Lines {
+			*(g.board.ColorAt(line.To.X, line.To.Y)) = graphics.NoColor
+			g.deleteLine(Line{From: line.From, To: line.To, Color: clr})
+		}
+		path.Lines = nil
+		g.updateDeadEnds()
+	}
+}
+
+// deleteLine removes the graphics object of a line.
+func (g *Game) deleteLine(l Line) {
+	delete(g.lineBounds, l)
+	delete(g.hinted, l)
+}
+
+// completePath connects a path to its end dot.
+func (g *Game) completePath(path *Path, end Dot) {
+	path.EndDot = &end
+
+	other, ok := g.board.Paths[end]
+	if !ok {
+		g.log.Fatal("Destination dot not found in paths",
+			zap.Int32("x", end.Location.X),
+			zap.Int32("y", end.Location.Y))
+	}
+	other.StartDot = &end
+	other.EndDot = path.StartDot
+}
+
 // MouseButtonUp handles the mouse button up events.
 func (g *Game) MouseButtonUp(ev *sdl.MouseButtonEvent) {
 	if !g.state.editingPath || g.state.srcDot == nil {
@@ -432,13 +1004,15 @@ func (g *Game) MouseButtonUp(ev *sdl.MouseButtonEvent) {
 		path.EndDot = g.state.srcDot
 
 		g.Moves++
-		if g.coverage == g.board.size*g.board.size {
-			g.Completed = true
-		}
+		g.checkCompleted()
 	} else {
 		path, ok := g.board.Paths[*g.state.srcDot]
-		if ok && len(path.Lines) > 0 {
-			for _, line := range path.Lines {
+		keep := g.state.keep
+		if ok && keep > len(path.Lines) {
+			keep = len(path.Lines)
+		}
+		if ok && len(path.Lines) > keep {
+			for _, line := range path.Lines[keep:] {
 				*(g.board.ColorAt(line.To.X, line.To.Y)) = graphics.NoColor
 
 				l := Line{
@@ -446,14 +1020,50 @@ func (g *Game) MouseButtonUp(ev *sdl.MouseButtonEvent) {
 					To:    line.To,
 					Color: g.state.color,
 				}
-				delete(g.lineBounds, l)
+				g.deleteLine(l)
 			}
-			path.Lines = nil
+			path.Lines = path.Lines[:keep]
 		}
 	}
 
 	g.coverage = g.board.Coverage()
 	g.state.reset()
+	g.autoComplete()
+	g.updateDeadEnds()
+}
+
+// checkCompleted updates the coverage and verifies the paths drawn
+// in order to check if the level is completed. If the board is covered
+// but the paths break some rule, the first violation is displayed.
+func (g *Game) checkCompleted() {
+	g.coverage = g.board.Coverage()
+
+	vs := Verify(g.level, g.drawnPaths())
+	if len(vs) == 0 {
+		g.Completed = true
+		g.recordDaily()
+		return
+	}
+
+	if g.coverage == g.board.Squares() {
+		g.message = vs[0].String()
+		g.log.Debug("The board is covered but the paths are wrong",
+			zap.String("violation", g.message))
+	}
+}
+
+// KeyDown handles the key down events.
+func (g *Game) KeyDown(ev *sdl.KeyboardEvent) {
+	switch ev.Keysym.Sym {
+	case sdl.K_h:
+		g.HintSegment()
+	case sdl.K_p:
+		g.HintPath()
+	case sdl.K_a:
+		g.ToggleAssist()
+	case sdl.K_u:
+		g.UndoAssist()
+	}
 }
 
 // MouseMove handles the mouse move events.
@@ -540,6 +1150,8 @@ func (g *Game) addLine(from, to Coordinate, clr graphics.Color, path *Path) {
 		path.EndDot = g.state.dstDot
 	}
 	*(g.board.ColorAt(to.X, to.Y)) = clr
+
+	g.updateDeadEnds()
 }
 
 func (g *Game) removeLine(from, to Coordinate, clr graphics.Color, path *Path) {
@@ -548,7 +1160,7 @@ func (g *Game) removeLine(from, to Coordinate, clr graphics.Color, path *Path) {
 		To:    from,
 		Color: clr,
 	}
-	delete(g.lineBounds, l)
+	g.deleteLine(l)
 	path.RemoveLine(l.From, l.To)
 
 	if g.state.dstDot == nil || (g.state.dstDot != nil && g.state.dstDot.Location != l.To) {
@@ -559,13 +1171,35 @@ func (g *Game) removeLine(from, to Coordinate, clr graphics.Color, path *Path) {
 		path.EndDot = nil
 		g.state.dstDot = nil
 	}
+
+	g.updateDeadEnds()
+}
+
+// updateDeadEnds looks for the dead ends of the board.
+func (g *Game) updateDeadEnds() {
+	g.deadEnds = nil
+	if g.level == nil {
+		return
+	}
+
+	de, err := FindDeadEnds(g.level, g.board)
+	if err != nil {
+		g.log.Debug("Failed to find the dead ends", zap.Error(err))
+		return
+	}
+
+	if !de.Empty() {
+		g.deadEnds = de
+	}
 }
 
 func (g *Game) nextAction(from, to Coordinate,
 	clrSrc graphics.Color, clrDst graphics.Color, path *Path) drawAction {
 
+	lastVisited := path.StartDot.Location
 	if len(path.Lines) > 0 {
-		if from != path.Lines[len(path.Lines)-1].To {
+		lastVisited = path.Lines[len(path.Lines)-1].To
+		if from != lastVisited {
 			// we did not get here from the current path
 			return none
 		}
@@ -578,7 +1212,7 @@ func (g *Game) nextAction(from, to Coordinate,
 		}
 
 		_, ok := g.board.Paths[dot]
-		if ok && dot != *g.state.srcDot && g.state.dstDot == nil {
+		if ok && dot != *g.state.srcDot && g.state.dstDot == nil && g.board.Open(lastVisited, to) {
 			return completePath
 		}
 
@@ -593,16 +1227,9 @@ func (g *Game) nextAction(from, to Coordinate,
 			return none
 		}
 
-		var lastVisited Coordinate
-		if len(path.Lines) > 0 {
-			lastVisited = path.Lines[len(path.Lines)-1].To
-		} else {
-			lastVisited = path.StartDot.Location
-		}
-
-		if !((to.X == lastVisited.X && math.Abs(float64(to.Y-lastVisited.Y)) == 1) ||
-			(to.Y == lastVisited.Y && math.Abs(float64(to.X-lastVisited.X)) == 1)) {
-			// we can only draw horizontal and vertical lines
+		if !g.board.Open(lastVisited, to) {
+			// we can only draw horizontal and vertical lines,
+			// neither through a wall nor on a blocked square
 			return none
 		}
 
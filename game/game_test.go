@@ -0,0 +1,172 @@
+package game
+
+import (
+	"bytes"
+	"connect-dots/config"
+	"connect-dots/graphics"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/veandco/go-sdl2/sdl"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// newTestGame creates a game for a level with the board drawn
+// at the origin of the screen and no textures.
+func newTestGame(l *Level, opts ...option) *Game {
+	cfg := config.New(func(c *config.Config) { c.Width, c.Height = l.Width, l.Height })
+
+	assets := graphics.NewAssetsStorage()
+	assets.Grid = graphics.NewGrid(&sdl.Rect{W: l.Width * cfg.SquareSize, H: l.Height * cfg.SquareSize}, nil)
+	for i := range graphics.Colors {
+		assets.Dots[graphics.Color(i)] = graphics.NewDot(&sdl.Rect{W: 2 * cfg.DotRadius, H: 2 * cfg.DotRadius}, nil)
+	}
+
+	return New(cfg, assets, append(opts, WithLevel(l))...)
+}
+
+// mouse returns a mouse event at the center of a square.
+func mouse(g *Game, c Coordinate) (*sdl.MouseButtonEvent, *sdl.MouseMotionEvent) {
+	x := c.X*g.config.SquareSize + g.config.SquareSize/2
+	y := c.Y*g.config.SquareSize + g.config.SquareSize/2
+	return &sdl.MouseButtonEvent{Button: sdl.BUTTON_LEFT, X: x, Y: y},
+		&sdl.MouseMotionEvent{X: x, Y: y}
+}
+
+// drag draws a line through the given squares with the mouse.
+func drag(g *Game, squares ...Coordinate) {
+	down, _ := mouse(g, squares[0])
+	g.MouseButtonDown(down)
+	for _, c := range squares[1:] {
+		_, move := mouse(g, c)
+		g.MouseMove(move)
+	}
+	up, _ := mouse(g, squares[len(squares)-1])
+	g.MouseButtonUp(up)
+}
+
+func TestAutoComplete(t *testing.T) {
+	l := mediumLevel(t)
+	g := newTestGame(l, WithAssist(true))
+	defer g.Close()
+
+	drag(g, Coordinate{0, 1}, Coordinate{0, 2})
+	assert.Equal(t, int32(1), g.Moves)
+	assert.True(t, g.AutoMoves > 0)
+
+	lines := 0
+	for _, p := range g.board.Paths {
+		lines += len(p.Lines)
+	}
+	assert.Equal(t, int(g.AutoMoves)+1, lines)
+
+	g.UndoAssist()
+	assert.Equal(t, int32(0), g.AutoMoves)
+	assert.Equal(t, int32(1), g.Moves)
+
+	lines = 0
+	for _, p := range g.board.Paths {
+		lines += len(p.Lines)
+	}
+	assert.Equal(t, 1, lines)
+	assert.Equal(t, int32(len(l.Dots)), g.board.Coverage())
+}
+
+func TestUndoAssistCancelsHint(t *testing.T) {
+	l := mediumLevel(t)
+	g := newTestGame(l, WithAssist(true))
+	defer g.Close()
+
+	drag(g, Coordinate{0, 1}, Coordinate{0, 2})
+	g.HintSegment()
+	assert.NotNil(t, g.hint)
+	g.UndoAssist()
+	assert.Nil(t, g.hint)
+
+	// toggling the assist mode keeps the hint asked for
+	g.HintSegment()
+	assert.NotNil(t, g.hint)
+	g.ToggleAssist()
+	assert.NotNil(t, g.hint)
+}
+
+func TestLevelName(t *testing.T) {
+	l := levelOf(t, 5, `[{"x": 0, "y": 0, "color": "red"}, {"x": 4, "y": 0, "color": "red"}]`)
+
+	g := newTestGame(l, WithFile("3.json"))
+	defer g.Close()
+	assert.Equal(t, "Level 5/3", g.LevelName())
+
+	l.Name, l.Author, l.Par = "Twins", "Ann", 1
+	assert.Equal(t, "Twins by Ann", g.LevelName())
+	assert.Equal(t, int32(1), g.Par())
+}
+
+func TestRectangularBoard(t *testing.T) {
+	l, err := Load(rectangularJson)
+	assert.Nil(t, err)
+	g := newTestGame(l)
+	defer g.Close()
+
+	// the squares right of the board are outside the grid
+	drag(g, Coordinate{3, 5}, Coordinate{4, 5})
+	assert.Equal(t, int32(0), g.Moves)
+
+	paths, err := Solve(l)
+	assert.Nil(t, err)
+	for _, p := range paths {
+		squares := []Coordinate{p.StartDot.Location}
+		for _, line := range p.Lines {
+			squares = append(squares, line.To)
+		}
+		drag(g, squares...)
+	}
+	assert.Equal(t, int32(24), g.board.Coverage())
+	assert.True(t, g.Completed)
+}
+
+func TestShapedBoard(t *testing.T) {
+	l, err := Load(ringJson)
+	assert.Nil(t, err)
+	l.Walls = []Wall{{Coordinate{0, 1}, Coordinate{0, 2}}}
+	g := newTestGame(l)
+	defer g.Close()
+
+	// no line on a blocked square
+	drag(g, Coordinate{0, 0}, Coordinate{1, 0}, Coordinate{1, 1}, Coordinate{2, 0}, Coordinate{3, 0})
+	assert.Equal(t, int32(0), g.Moves)
+	drag(g, Coordinate{0, 0}, Coordinate{1, 0}, Coordinate{2, 0}, Coordinate{3, 0})
+	assert.Equal(t, int32(1), g.Moves)
+
+	// nor through a wall
+	drag(g, Coordinate{0, 1}, Coordinate{0, 2}, Coordinate{0, 3}, Coordinate{1, 3},
+		Coordinate{2, 3}, Coordinate{3, 3}, Coordinate{3, 2}, Coordinate{3, 1})
+	assert.Equal(t, int32(1), g.Moves)
+	assert.Equal(t, int32(12), g.board.Squares())
+	assert.Equal(t, int32(6), g.board.Coverage())
+}
+
+func TestSolveFailedLoggedOnce(t *testing.T) {
+	// the paths would cross
+	l := levelOf(t, 2, `[{"x": 0, "y": 0, "color": "red"}, {"x": 1, "y": 1, "color": "red"},
+		{"x": 1, "y": 0, "color": "blue"}, {"x": 0, "y": 1, "color": "blue"}]`)
+
+	var buf bytes.Buffer
+	log := zap.New(zapcore.NewCore(zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig()),
+		zapcore.AddSync(&buf), zap.DebugLevel))
+	g := newTestGame(l, WithLogger(log))
+	defer g.Close()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for !g.solve.finished() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, ErrNoSolution, g.solve.err)
+
+	g.Update()
+	g.Update()
+	assert.Equal(t, 1, strings.Count(buf.String(), "Failed to solve the level"))
+}
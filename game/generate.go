@@ -0,0 +1,294 @@
+package game
+
+import (
+	"connect-dots/graphics"
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// minPathLength is the minimum number of squares covered by
+// a generated path (its dots included).
+const minPathLength = 3
+
+// palette is the list of the colors given to the generated paths: the
+// built-in colors (magenta comes last, so the levels generated with fewer
+// colors are the same as before it was added), then the colors of the
+// tableau20 palette for the levels having more colors.
+var palette = append([]graphics.Color{
+	graphics.Red,
+	graphics.Green,
+	graphics.Blue,
+	graphics.Yellow,
+	graphics.Cyan,
+	graphics.Pink,
+	graphics.Orange,
+	graphics.Brown,
+	graphics.White,
+	graphics.Black,
+	graphics.Magenta,
+}, graphics.PaletteColors("tableau20")...)
+
+// generateAttempts is the number of times the dots of a level are moved
+// in order to meet the constraints (or to make its solution unique),
+// before giving up.
+const generateAttempts = 100
+
+type generateConfig struct {
+	// True if the level must have a unique solution.
+	unique bool
+
+	// The constraints the level must meet.
+	constraints Constraints
+}
+
+// GenerateOption configures Generate.
+type GenerateOption func(*generateConfig)
+
+// WithUniqueSolution makes Generate emit only the levels
+// having exactly one solution.
+func WithUniqueSolution() GenerateOption {
+	return func(c *generateConfig) {
+		c.unique = true
+	}
+}
+
+// Generate produces a random level having a solution which covers the
+// whole board. The same seed always produces the same level.
+//
+// A random path visiting every square is grown first: starting from a
+// zigzag, the path is reshaped many times by joining one of its ends to
+// an adjacent square of the path and reversing the part in between
+// (a "backbite" move). The path is then cut into the given number of
+// pieces, at least minPathLength squares long, and the dots are placed
+// at the ends of the pieces.
+//
+// If the level must have a unique solution, the pieces are reshaped by
+// backbite moves between them (see partition) until no piece touches
+// itself, which leaves few solutions besides the pieces themselves.
+// The other solutions are then looked for with the SAT solver and the
+// pieces are moved around until none is left.
+//
+// The pieces are moved around the same way until the level meets the
+// constraints (see WithConstraints). If it fails, a ConstraintError
+// reports the constraint which failed the most often.
+func Generate(size int32, colors int, seed int64, opts ...GenerateOption) (*Level, error) {
+	return GenerateContext(context.Background(), size, colors, seed, opts...)
+}
+
+// GenerateContext is Generate with a context: the generation is cancelled
+// with the context (in which case the error of the context is returned).
+func GenerateContext(ctx context.Context, size int32, colors int, seed int64,
+	opts ...GenerateOption) (*Level, error) {
+	var cfg generateConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if size < 2 || size > maxBoardSize {
+		return nil, fmt.Errorf("Invalid value for size: %d", size)
+	}
+
+	n := int(size * size)
+	c := &cfg.constraints
+	if c.Colors != 0 {
+		if c.Colors < 0 || c.Colors > len(palette) || c.Colors*minPathLength > n {
+			return nil, &ConstraintError{Size: size, Colors: c.Colors, Constraint: ColorsConstraint}
+		}
+		colors = c.Colors
+	}
+	if colors <= 0 || colors > len(palette) || colors*minPathLength > n {
+		return nil, fmt.Errorf("Invalid value for colors: %d", colors)
+	}
+
+	r := rand.New(rand.NewSource(seed))
+	p, err := newConstrainedPartition(size, colors, c, r)
+	if err != nil {
+		return nil, err
+	}
+
+	failures := make([]int, len(constraintNames))
+	for attempt := 0; attempt < generateAttempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if cfg.unique {
+			p = p.untangle(r, 50*n)
+		}
+
+		l, failed, err := constrained(ctx, p, c, cfg.unique)
+		if err != nil {
+			return nil, err
+		}
+		if l != nil {
+			return l, nil
+		}
+		failures[failed]++
+
+		// move the dots a little and try again
+		for m := 0; m < int(size); m++ {
+			p.backbite(r)
+		}
+	}
+
+	failed := 0
+	for i, count := range failures {
+		if count > failures[failed] {
+			failed = i
+		}
+	}
+	return nil, &ConstraintError{Size: size, Colors: colors, Constraint: Constraint(failed), Attempts: generateAttempts}
+}
+
+// constrained returns the level of a partition if it meets the constraints
+// (and has a unique solution if required), or else the first constraint
+// the level fails.
+func constrained(ctx context.Context, p *partition, c *Constraints, unique bool) (*Level, Constraint, error) {
+	if failed, ok := c.check(p.size, p.all()); ok {
+		return nil, failed, nil
+	}
+
+	l, solution := p.level()
+	if unique {
+		other, err := otherSolution(ctx, l, solution)
+		if err != nil {
+			return nil, 0, err
+		}
+		if other != nil {
+			return nil, UniquenessConstraint, nil
+		}
+	}
+
+	l, err := graded(l)
+	if err != nil {
+		return nil, 0, err
+	}
+	if c.Difficulty != nil && l.Difficulty != *c.Difficulty {
+		return nil, DifficultyConstraint, nil
+	}
+	return l, 0, nil
+}
+
+// graded sets the difficulty of a generated level,
+// and its par: a move per color.
+func graded(l *Level) (*Level, error) {
+	l.Par = int32(len(l.Dots) / 2)
+
+	d, err := newDeducer(l)
+	if err != nil {
+		return nil, err
+	}
+	l.Difficulty, _ = d.grade()
+	return l, nil
+}
+
+// randomLengths splits n squares into pieces between min and max squares
+// long (no maximum if 0): each piece gets the minimum length plus a random
+// share of the squares left (given one at a time if there is a maximum).
+func randomLengths(n, pieces, min, max int, r *rand.Rand) []int {
+	if max > 0 {
+		lengths := make([]int, pieces)
+		for i := range lengths {
+			lengths[i] = min
+		}
+		for extra := n - pieces*min; extra > 0; extra-- {
+			var room []int
+			for i, length := range lengths {
+				if length < max {
+					room = append(room, i)
+				}
+			}
+			lengths[room[r.Intn(len(room))]]++
+		}
+		return lengths
+	}
+
+	extra := n - pieces*min
+	cuts := make([]int, pieces-1)
+	for i := range cuts {
+		cuts[i] = r.Intn(extra + 1)
+	}
+	sort.Ints(cuts)
+	cuts = append(cuts, extra)
+
+	lengths := make([]int, pieces)
+	prev := 0
+	for i, c := range cuts {
+		lengths[i] = min + c - prev
+		prev = c
+	}
+	return lengths
+}
+
+// hamiltonianPath returns a random path visiting every square of a board
+// once. The squares are indexed the same way the Board does: x*size+y.
+func hamiltonianPath(size int32, r *rand.Rand) []int {
+	return regionPath(size, size, r)
+}
+
+// regionPath returns a random path visiting once every square of the
+// first columns of a board (the squares 0 to columns*size-1).
+func regionPath(size, columns int32, r *rand.Rand) []int {
+	n := int(columns * size)
+	adj := regionNeighbours(size, columns)
+
+	// a zigzag through the columns
+	path := make([]int, 0, n)
+	for x := int32(0); x < columns; x++ {
+		for y := int32(0); y < size; y++ {
+			if x%2 == 1 {
+				path = append(path, int(x*size+size-1-y))
+			} else {
+				path = append(path, int(x*size+y))
+			}
+		}
+	}
+
+	pos := make([]int, n)
+	for i, sq := range path {
+		pos[sq] = i
+	}
+
+	reverse := func(i, j int) {
+		for ; i < j; i, j = i+1, j-1 {
+			path[i], path[j] = path[j], path[i]
+			pos[path[i]] = i
+			pos[path[j]] = j
+		}
+	}
+
+	moves := 10 * n * int(size)
+	for m := 0; m < moves; m++ {
+		// backbite from the tail: reversing the path makes the head the tail
+		if r.Intn(2) == 0 {
+			reverse(0, n-1)
+		}
+
+		tail := path[n-1]
+		next := adj[tail][r.Intn(len(adj[tail]))]
+		if p := pos[next]; p != n-2 {
+			reverse(p+1, n-1)
+		}
+	}
+
+	return path
+}
+
+// regionNeighbours returns the neighbours of the squares of the first
+// columns of a board, inside these columns.
+func regionNeighbours(size, columns int32) [][]int {
+	n := int(columns * size)
+	adj := neighbours(size, size)[:n]
+	for sq, ns := range adj {
+		var inside []int
+		for _, s := range ns {
+			if s < n {
+				inside = append(inside, s)
+			}
+		}
+		adj[sq] = inside
+	}
+	return adj
+}
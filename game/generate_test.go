@@ -0,0 +1,74 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	for size := int32(5); size <= maxBoardSize; size++ {
+		colors := int(size)
+		l, err := Generate(size, colors, int64(size))
+		if !assert.Nil(t, err) {
+			continue
+		}
+		assert.Len(t, l.Dots, 2*colors)
+
+		data, err := l.Marshal()
+		assert.Nil(t, err)
+		m, err := Load(data)
+		assert.Nil(t, err)
+		assert.Equal(t, l, m)
+
+		paths, err := SolveSAT(l)
+		assert.Nil(t, err)
+		assert.Empty(t, Verify(l, paths))
+	}
+}
+
+func TestGenerateSeed(t *testing.T) {
+	a, err := Generate(7, 6, 42)
+	assert.Nil(t, err)
+	b, err := Generate(7, 6, 42)
+	assert.Nil(t, err)
+	c, err := Generate(7, 6, 43)
+	assert.Nil(t, err)
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+}
+
+func TestGenerateInvalid(t *testing.T) {
+	_, err := Generate(1, 1, 0)
+	assert.NotNil(t, err)
+
+	_, err = Generate(5, 0, 0)
+	assert.NotNil(t, err)
+
+	_, err = Generate(5, 9, 0)
+	assert.NotNil(t, err)
+}
+
+func TestGenerateUnique(t *testing.T) {
+	for size := int32(5); size <= maxBoardSize; size++ {
+		l, err := Generate(size, int(size), int64(size), WithUniqueSolution())
+		if !assert.Nil(t, err) {
+			continue
+		}
+
+		// the backtracking solver cross-checks the small boards
+		if size <= 6 {
+			unique, err := IsUnique(l)
+			assert.Nil(t, err)
+			assert.True(t, unique)
+		}
+
+		paths, err := SolveSAT(l)
+		assert.Nil(t, err)
+		other, err := otherSolution(context.Background(), l, paths)
+		assert.Nil(t, err)
+		assert.Nil(t, other)
+	}
+}
@@ -0,0 +1,230 @@
+package game
+
+import (
+	"connect-dots/graphics"
+	"context"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+type hintKind int
+
+const (
+	// reveal the next line of a path
+	hintSegment hintKind = iota
+	// reveal a whole path
+	hintPath
+)
+
+// findHint solves a level keeping the paths drawn by the player.
+// If no solution extends them, it returns the drawn path to blame instead:
+// a path which cannot be part of any solution on its own or else a path
+// the other drawn paths fit without. The offending path is nil if the
+// drawn paths only fail together.
+func findHint(ctx context.Context, level *Level, drawn []*Path,
+	opts ...SolveOption) ([]*Path, *Path, error) {
+
+	solve := func(fixed []*Path) ([]*Path, error) {
+		return SolveContext(ctx, level, append(opts, WithFixedPaths(fixed))...)
+	}
+
+	paths, err := solve(drawn)
+	if err != ErrNoSolution || len(drawn) == 0 {
+		return paths, nil, err
+	}
+
+	for _, p := range drawn {
+		_, err := solve([]*Path{p})
+		if err == ErrNoSolution {
+			return nil, p, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for i := len(drawn) - 1; i >= 0; i-- {
+		others := make([]*Path, 0, len(drawn)-1)
+		others = append(others, drawn[:i]...)
+		others = append(others, drawn[i+1:]...)
+
+		_, err := solve(others)
+		if err == nil {
+			return nil, drawn[i], nil
+		}
+		if err != ErrNoSolution {
+			return nil, nil, err
+		}
+	}
+
+	return nil, nil, ErrNoSolution
+}
+
+// drawnPaths returns copies of the paths drawn on the board (complete
+// or not), in the order their colors appear in the level.
+func (g *Game) drawnPaths() []*Path {
+	order := make(map[Dot]int)
+	for i, dot := range g.level.Dots {
+		order[dot] = i
+	}
+
+	var drawn []*Path
+	for _, p := range g.board.Paths {
+		if len(p.Lines) > 0 {
+			drawn = append(drawn, &Path{
+				StartDot: p.StartDot,
+				EndDot:   p.EndDot,
+				Lines:    append([]*Line(nil), p.Lines...),
+			})
+		}
+	}
+
+	sort.Slice(drawn, func(i, j int) bool {
+		return order[*drawn[i].StartDot] < order[*drawn[j].StartDot]
+	})
+	return drawn
+}
+
+// Hint asks for help: the next line of a path (hintSegment) or
+// a whole path (hintPath) of a solution which extends the paths
+// drawn by the player. The solve runs in the background and the hint
+// is applied by Update.
+//
+// The next line is preferably a move found by a deduction technique,
+// so the hint comes with the reason why the move is forced.
+func (g *Game) Hint(kind hintKind) {
+	if g.level == nil || g.Completed || g.state.editingPath || g.hint != nil {
+		return
+	}
+
+	g.message = ""
+	g.offending = nil
+	g.explanation = ""
+	g.explained = nil
+	g.deduction = nil
+
+	if kind == hintSegment {
+		ded, err := Explain(g.level, g.board)
+		if err != nil {
+			g.log.Info("Failed to explain the next move", zap.Error(err))
+		}
+		g.deduction = ded
+	}
+
+	g.hintKind = kind
+	g.hint = startSolve(g.level, g.drawnPaths())
+}
+
+// HintSegment reveals the next line of a path.
+func (g *Game) HintSegment() {
+	g.Hint(hintSegment)
+}
+
+// HintPath reveals a whole path.
+func (g *Game) HintPath() {
+	g.Hint(hintPath)
+}
+
+// applyHint draws the hinted lines once the hint solve ends.
+func (g *Game) applyHint() {
+	h := g.hint
+	g.hint = nil
+	g.assisted = nil
+
+	if h.offending != nil {
+		g.offending = h.offending
+		g.message = "The " + h.offending.StartDot.Color.String() + " path is wrong"
+		return
+	}
+
+	if h.err != nil {
+		g.message = "The paths drawn cannot be completed"
+		g.log.Info("Hint failed", zap.Error(h.err))
+		return
+	}
+
+	if g.deduction != nil && g.applyDeduction(g.deduction) {
+		g.Hints++
+		g.checkCompleted()
+		return
+	}
+
+	for _, sol := range h.paths {
+		path, ok := g.board.Paths[*sol.StartDot]
+		if !ok || path.EndDot != nil {
+			continue
+		}
+
+		n := len(sol.Lines)
+		if g.hintKind == hintSegment && len(path.Lines) < n {
+			n = len(path.Lines) + 1
+		}
+
+		for _, l := range sol.Lines[len(path.Lines):n] {
+			g.addLine(l.From, l.To, l.Color, path)
+			g.hinted[*l] = true
+		}
+
+		if len(path.Lines) == len(sol.Lines) {
+			g.completePath(path, *sol.EndDot)
+		}
+
+		if g.hintKind == hintSegment {
+			g.explanation = "No deduction technique applies here: the solver found the move"
+			g.explained = []Coordinate{sol.Lines[n-1].From, sol.Lines[n-1].To}
+		}
+
+		g.Hints++
+		break
+	}
+
+	g.checkCompleted()
+}
+
+// applyDeduction draws the line of a move found by a deduction technique
+// and explains it. It returns false if the move does not extend a path
+// of the board.
+func (g *Game) applyDeduction(ded *Deduction) bool {
+	path := g.pathEndingAt(ded.From, ded.Color)
+	if path == nil {
+		return false
+	}
+
+	g.extendPath(path, ded.From, ded.To, ded.Color)
+	g.hinted[Line{From: ded.From, To: ded.To, Color: ded.Color}] = true
+
+	g.explanation = ded.Reason()
+	g.explained = ded.Cells
+	return true
+}
+
+// pathEndingAt returns the uncompleted path of a color whose last
+// square (or start dot, if no line was drawn yet) is c.
+func (g *Game) pathEndingAt(c Coordinate, clr graphics.Color) *Path {
+	for _, path := range g.board.Paths {
+		if path.EndDot != nil || path.StartDot.Color != clr {
+			continue
+		}
+
+		n := len(path.Lines)
+		if (n == 0 && path.StartDot.Location == c) || (n > 0 && path.Lines[n-1].To == c) {
+			return path
+		}
+	}
+	return nil
+}
+
+// extendPath draws a line of a path and completes the path
+// if the line reaches its end dot. It reports whether the path
+// was completed.
+func (g *Game) extendPath(path *Path, from, to Coordinate, clr graphics.Color) bool {
+	g.addLine(from, to, clr, path)
+
+	end := Dot{Location: to, Color: clr}
+	if _, ok := g.board.Paths[end]; ok {
+		g.completePath(path, end)
+		return true
+	}
+	return false
+}
@@ -9,11 +9,34 @@ import (
 )
 
 // Level is a struct which stores the configuration of game level:
-// - the board size
+// - the board dimensions and shape
 // - the dots (colors and board coordinations)
+// - the difficulty
+// - the metadata (name, author, par and description)
 type Level struct {
-	// Size is the size of the board (5,6,7,8,9 or 10).
-	Size int32
+	// The width (the number of columns) and the height (the number of rows)
+	// of the board, the same for a square board (5,6,7,8,9 or 10).
+	Width  int32
+	Height int32
+
+	// Difficulty is the grade of the level (see Rate).
+	Difficulty Difficulty
+
+	// The name of the level and its author (optional).
+	Name   string
+	Author string
+
+	// Par is the number of moves an expert player needs (0 if unknown).
+	Par int32
+
+	// Description is a note about the level (optional).
+	Description string
+
+	// The squares which cannot be entered (the holes making the shape
+	// of an irregular board) and the walls between adjacent squares
+	// (both optional).
+	Blocked []Coordinate
+	Walls   []Wall
 
 	// The dots loaded from the file level.
 	Dots []Dot
@@ -27,7 +50,7 @@ type Level struct {
 //
 // where:
 // - data is a directory relative to the working directory
-// - <n> is the board dimension (5,6,7,8,9,10)
+// - <n> is the board width (5,6,7,8,9,10)
 // - <m> is the m-th file in the directory where we look for the level file
 func LoadFromFile(path string) (*Level, error) {
 	data, err := ioutil.ReadFile(path)
@@ -40,57 +63,60 @@ func LoadFromFile(path string) (*Level, error) {
 
 // Load decodes a Json Blob and instantiate a Level struct.
 func Load(data []byte) (*Level, error) {
-	var level struct {
-		Size int32 `json:"size"`
-		Dots []struct {
-			X     int32  `json:"x"`
-			Y     int32  `json:"y"`
-			Color string `json:"color"`
-		}
-	}
+	var level levelFile
 
 	err := json.Unmarshal(data, &level)
 	if err != nil {
 		return nil, err
 	}
 
-	if level.Size <= 0 {
-		return nil, fmt.Errorf("Invalid value for size: %d", level.Size)
+	width, height, err := level.dimensions()
+	if err != nil {
+		return nil, err
+	}
+
+	if !level.Difficulty.IsValid() {
+		return nil, fmt.Errorf("Invalid value for difficulty: %d", level.Difficulty)
+	}
+
+	if level.Par < 0 {
+		return nil, fmt.Errorf("Invalid value for par: %d", level.Par)
 	}
 
 	if len(level.Dots) == 0 {
 		return nil, errors.New("No dots found in the level file")
 	}
 
+	var blocked []Coordinate
+	for _, sq := range level.Blocked {
+		blocked = append(blocked, Coordinate{sq.X, sq.Y})
+	}
+	var walls []Wall
+	for _, w := range level.Walls {
+		walls = append(walls, Wall{Coordinate{w.From.X, w.From.Y}, Coordinate{w.To.X, w.To.Y}})
+	}
+	if err := validateShape(width, height, blocked, walls); err != nil {
+		return nil, err
+	}
+
+	if err := validateDots(width, height, blocked, level.Dots); err != nil {
+		return nil, err
+	}
+
 	l := &Level{}
 
-	l.Size = level.Size
+	l.Width = width
+	l.Height = height
+	l.Difficulty = level.Difficulty
+	l.Name = level.Name
+	l.Author = level.Author
+	l.Par = level.Par
+	l.Description = level.Description
+	l.Blocked = blocked
+	l.Walls = walls
 	l.Dots = []Dot{}
 	for _, dot := range level.Dots {
-		var c graphics.Color
-		switch dot.Color {
-		case "red":
-			c = graphics.Red
-		case "green":
-			c = graphics.Green
-		case "blue":
-			c = graphics.Blue
-		case "yellow":
-			c = graphics.Yellow
-		case "cyan":
-			c = graphics.Cyan
-		case "pink":
-			c = graphics.Pink
-		case "orange":
-			c = graphics.Orange
-		case "brown":
-			c = graphics.Brown
-		case "white":
-			c = graphics.White
-		case "black":
-			c = graphics.Black
-		}
-
+		c, _ := graphics.ParseColor(dot.Color)
 		l.Dots = append(l.Dots, Dot{
 			Location: Coordinate{dot.X, dot.Y},
 			Color:    c,
@@ -99,3 +125,185 @@ func Load(data []byte) (*Level, error) {
 
 	return l, nil
 }
+
+// Colors returns the colors of the dots (each color once,
+// in the order the colors appear).
+func (l *Level) Colors() []graphics.Color {
+	var colors []graphics.Color
+	seen := make(map[graphics.Color]bool)
+	for _, dot := range l.Dots {
+		if !seen[dot.Color] {
+			seen[dot.Color] = true
+			colors = append(colors, dot.Color)
+		}
+	}
+	return colors
+}
+
+// IsBlocked checks if a square of the board cannot be entered.
+func (l *Level) IsBlocked(c Coordinate) bool {
+	for _, b := range l.Blocked {
+		if b == c {
+			return true
+		}
+	}
+	return false
+}
+
+// SameShape checks if the boards of two levels have the same dimensions,
+// the same blocked squares and the same walls (in any order).
+func (l *Level) SameShape(o *Level) bool {
+	if l.Width != o.Width || l.Height != o.Height ||
+		len(l.Blocked) != len(o.Blocked) || len(l.Walls) != len(o.Walls) {
+		return false
+	}
+
+	for _, c := range o.Blocked {
+		if !l.IsBlocked(c) {
+			return false
+		}
+	}
+
+	walls := make(map[Wall]bool)
+	for _, w := range l.Walls {
+		walls[w] = true
+		walls[Wall{w.To, w.From}] = true
+	}
+	for _, w := range o.Walls {
+		if !walls[w] {
+			return false
+		}
+	}
+	return true
+}
+
+// SameDots checks if two levels have the same board and the same dots
+// (in any order).
+func (l *Level) SameDots(o *Level) bool {
+	if !l.SameShape(o) || len(l.Dots) != len(o.Dots) {
+		return false
+	}
+
+	dots := make(map[Dot]bool)
+	for _, dot := range l.Dots {
+		dots[dot] = true
+	}
+	for _, dot := range o.Dots {
+		if !dots[dot] {
+			return false
+		}
+	}
+	return true
+}
+
+// Square checks if the board of the level is a square.
+func (l *Level) Square() bool {
+	return l.Width == l.Height
+}
+
+// levelFile is a level as stored in the level files: the dimensions of
+// a square board are given by its size, the ones of a rectangular board
+// by its width and height.
+type levelFile struct {
+	Size        int32         `json:"size,omitempty"`
+	Width       int32         `json:"width,omitempty"`
+	Height      int32         `json:"height,omitempty"`
+	Name        string        `json:"name,omitempty"`
+	Author      string        `json:"author,omitempty"`
+	Difficulty  Difficulty    `json:"difficulty"`
+	Par         int32         `json:"par,omitempty"`
+	Description string        `json:"description,omitempty"`
+	Blocked     []levelSquare `json:"blocked,omitempty"`
+	Walls       []levelWall   `json:"walls,omitempty"`
+	Dots        []levelDot    `json:"dots"`
+}
+
+// dimensions returns the width and the height of the board.
+func (level *levelFile) dimensions() (int32, int32, error) {
+	if level.Width == 0 && level.Height == 0 {
+		if level.Size <= 0 {
+			return 0, 0, fmt.Errorf("Invalid value for size: %d", level.Size)
+		}
+		return level.Size, level.Size, nil
+	}
+
+	if level.Size != 0 {
+		return 0, 0, errors.New("Both the size and the width and height of the board are given")
+	}
+	if level.Width <= 0 {
+		return 0, 0, fmt.Errorf("Invalid value for width: %d", level.Width)
+	}
+	if level.Height <= 0 {
+		return 0, 0, fmt.Errorf("Invalid value for height: %d", level.Height)
+	}
+	return level.Width, level.Height, nil
+}
+
+// levelSquare is a square as stored in the level files.
+type levelSquare struct {
+	X int32 `json:"x"`
+	Y int32 `json:"y"`
+}
+
+// levelWall is a wall as stored in the level files.
+type levelWall struct {
+	From levelSquare `json:"from"`
+	To   levelSquare `json:"to"`
+}
+
+// levelDot is a dot as stored in the level files.
+type levelDot struct {
+	X     int32  `json:"x"`
+	Y     int32  `json:"y"`
+	Color string `json:"color"`
+}
+
+// Marshal encodes the level in the format read by Load.
+func (l *Level) Marshal() ([]byte, error) {
+	level := levelFile{
+		Name:        l.Name,
+		Author:      l.Author,
+		Difficulty:  l.Difficulty,
+		Par:         l.Par,
+		Description: l.Description,
+	}
+	if l.Square() {
+		level.Size = l.Width
+	} else {
+		level.Width, level.Height = l.Width, l.Height
+	}
+
+	for _, c := range l.Blocked {
+		level.Blocked = append(level.Blocked, levelSquare{c.X, c.Y})
+	}
+	for _, w := range l.Walls {
+		level.Walls = append(level.Walls, levelWall{
+			From: levelSquare{w.From.X, w.From.Y},
+			To:   levelSquare{w.To.X, w.To.Y},
+		})
+	}
+
+	for _, dot := range l.Dots {
+		level.Dots = append(level.Dots, levelDot{
+			X:     dot.Location.X,
+			Y:     dot.Location.Y,
+			Color: dot.Color.String(),
+		})
+	}
+
+	data, err := json.MarshalIndent(&level, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// SaveToFile writes the level data to a file (see LoadFromFile).
+func (l *Level) SaveToFile(path string) error {
+	data, err := l.Marshal()
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+}
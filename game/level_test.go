@@ -1,6 +1,7 @@
 package game
 
 import (
+	"connect-dots/graphics"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -68,7 +69,8 @@ var blobJson = []byte(`
 func TestLoad(t *testing.T) {
 	l, err := Load(blobJson)
 	assert.Nil(t, err)
-	assert.Equal(t, l.Size, int32(5))
+	assert.Equal(t, l.Width, int32(5))
+	assert.Equal(t, l.Height, int32(5))
 }
 
 func TestLoadWrongSizeValue(t *testing.T) {
@@ -124,3 +126,205 @@ func TestLoadWrongDifficultyValue(t *testing.T) {
 	assert.Nil(t, l)
 	assert.NotNil(t, err)
 }
+
+func TestLoadInvalidDots(t *testing.T) {
+	tests := []struct {
+		dots string
+		errs DotErrors
+	}{
+		{
+			`{"x": 0, "y": 0, "color": "red"}, {"x": 5, "y": 1, "color": "red"}`,
+			DotErrors{{Kind: DotOutsideBoard, Index: 1, Location: Coordinate{5, 1}, Color: "red"}},
+		},
+		{
+			`{"x": 0, "y": 0, "color": "red"}, {"x": 1, "y": 1, "color": "purple"}`,
+			DotErrors{
+				{Kind: SingleDot, Index: 0, Location: Coordinate{0, 0}, Color: "red"},
+				{Kind: UnknownColor, Index: 1, Location: Coordinate{1, 1}, Color: "purple"},
+			},
+		},
+		{
+			`{"x": 0, "y": 0, "color": "red"}, {"x": 1, "y": 1, "color": "red"},
+			 {"x": 0, "y": 0, "color": "blue"}, {"x": 2, "y": 2, "color": "blue"}`,
+			DotErrors{{Kind: DuplicateDot, Index: 2, Location: Coordinate{0, 0}, Color: "blue", Others: []int{0}}},
+		},
+		{
+			`{"x": 0, "y": 0, "color": "red"}, {"x": 1, "y": 1, "color": "red"}, {"x": 2, "y": 2, "color": "red"}`,
+			DotErrors{{Kind: ExtraDot, Index: 2, Location: Coordinate{2, 2}, Color: "red", Others: []int{0, 1}}},
+		},
+	}
+
+	for _, test := range tests {
+		l, err := Load([]byte(`{"size": 5, "dots": [` + test.dots + `]}`))
+		assert.Nil(t, l)
+		assert.Equal(t, test.errs, err)
+	}
+
+	err := DotErrors{{Kind: ExtraDot, Index: 2, Location: Coordinate{2, 2}, Color: "red", Others: []int{0, 1}}}
+	assert.Equal(t, `Dot 2 at (2,2) of color "red" is the third dot of its color (the first two are dots 0 and 1)`,
+		err.Error())
+}
+
+func TestLoadMagenta(t *testing.T) {
+	l, err := Load([]byte(`{"size": 5, "dots": [
+		{"x": 0, "y": 0, "color": "magenta"}, {"x": 4, "y": 4, "color": "magenta"}]}`))
+	assert.Nil(t, err)
+	assert.Equal(t, graphics.Magenta, l.Dots[0].Color)
+}
+
+func TestLoadMetadata(t *testing.T) {
+	l, err := Load([]byte(`{"size": 5, "name": "Twins", "author": "Ann", "difficulty": 2, "par": 2,
+		"description": "Two long paths", "dots": [
+		{"x": 0, "y": 0, "color": "red"}, {"x": 4, "y": 0, "color": "red"},
+		{"x": 0, "y": 1, "color": "blue"}, {"x": 4, "y": 4, "color": "blue"}]}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "Twins", l.Name)
+	assert.Equal(t, "Ann", l.Author)
+	assert.Equal(t, Hard, l.Difficulty)
+	assert.Equal(t, int32(2), l.Par)
+	assert.Equal(t, "Two long paths", l.Description)
+
+	data, err := l.Marshal()
+	assert.Nil(t, err)
+	m, err := Load(data)
+	assert.Nil(t, err)
+	assert.Equal(t, l, m)
+
+	_, err = Load([]byte(`{"size": 5, "par": -1, "dots": [
+		{"x": 0, "y": 0, "color": "red"}, {"x": 4, "y": 0, "color": "red"}]}`))
+	assert.NotNil(t, err)
+}
+
+func TestLoadCustomColors(t *testing.T) {
+	l, err := Load([]byte(`{"size": 5, "dots": [
+		{"x": 0, "y": 0, "color": "#FF8800"}, {"x": 4, "y": 0, "color": "#ff8800"},
+		{"x": 0, "y": 1, "color": "tableau20:3"}, {"x": 4, "y": 1, "color": "tableau20:3"},
+		{"x": 0, "y": 2, "color": "#ff0000"}, {"x": 4, "y": 2, "color": "red"}]}`))
+	assert.Nil(t, err)
+	assert.True(t, l.Dots[0].Color.IsValid())
+	assert.Equal(t, l.Dots[0].Color, l.Dots[1].Color)
+	assert.Equal(t, "#ff8800", l.Dots[0].Color.String())
+	assert.Equal(t, graphics.Red, l.Dots[4].Color)
+	assert.Len(t, l.Colors(), 3)
+
+	data, err := l.Marshal()
+	assert.Nil(t, err)
+	m, err := Load(data)
+	assert.Nil(t, err)
+	assert.Equal(t, l, m)
+
+	// the value of a palette color keeps its name
+	l, err = Load([]byte(`{"size": 5, "dots": [
+		{"x": 0, "y": 0, "color": "tableau20:0"}, {"x": 4, "y": 0, "color": "tableau20:0"},
+		{"x": 0, "y": 1, "color": "#1f77b4"}, {"x": 4, "y": 1, "color": "#1f77b4"}]}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "tableau20:0", l.Dots[0].Color.String())
+	assert.Equal(t, "#1f77b4", l.Dots[2].Color.String())
+	assert.Equal(t, l.Dots[0].Color.RGBA(), l.Dots[2].Color.RGBA())
+
+	for _, clr := range []string{"#ff880", "#gg8800", "tableau20:20", "nopalette:0"} {
+		_, err = Load([]byte(`{"size": 5, "dots": [
+			{"x": 0, "y": 0, "color": "` + clr + `"}, {"x": 4, "y": 0, "color": "` + clr + `"}]}`))
+		if assert.IsType(t, DotErrors{}, err, clr) {
+			assert.Equal(t, UnknownColor, err.(DotErrors)[0].Kind)
+		}
+	}
+}
+
+// rectangularJson is a level of a board 4 squares wide and 6 squares high.
+var rectangularJson = []byte(`{"width": 4, "height": 6, "dots": [
+	{"x": 0, "y": 0, "color": "red"}, {"x": 3, "y": 0, "color": "red"},
+	{"x": 0, "y": 1, "color": "green"}, {"x": 1, "y": 1, "color": "blue"}, {"x": 3, "y": 1, "color": "blue"},
+	{"x": 1, "y": 2, "color": "yellow"}, {"x": 2, "y": 2, "color": "cyan"}, {"x": 3, "y": 2, "color": "cyan"},
+	{"x": 2, "y": 3, "color": "pink"}, {"x": 3, "y": 3, "color": "orange"},
+	{"x": 0, "y": 5, "color": "green"}, {"x": 1, "y": 5, "color": "yellow"},
+	{"x": 2, "y": 5, "color": "pink"}, {"x": 3, "y": 5, "color": "orange"}]}`)
+
+func TestLoadRectangular(t *testing.T) {
+	l, err := Load(rectangularJson)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(4), l.Width)
+	assert.Equal(t, int32(6), l.Height)
+	assert.False(t, l.Square())
+
+	data, err := l.Marshal()
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), `"width": 4`)
+	assert.NotContains(t, string(data), `"size"`)
+	m, err := Load(data)
+	assert.Nil(t, err)
+	assert.Equal(t, l, m)
+
+	for _, json := range []string{
+		`{"size": 4, "width": 4, "height": 6, "dots": [{"x": 0, "y": 0, "color": "red"}, {"x": 1, "y": 0, "color": "red"}]}`,
+		`{"height": 6, "dots": [{"x": 0, "y": 0, "color": "red"}, {"x": 1, "y": 0, "color": "red"}]}`,
+		`{"width": 4, "height": -6, "dots": [{"x": 0, "y": 0, "color": "red"}, {"x": 1, "y": 0, "color": "red"}]}`,
+	} {
+		_, err = Load([]byte(json))
+		assert.NotNil(t, err, json)
+	}
+
+	_, err = Load([]byte(`{"width": 4, "height": 6, "dots": [
+		{"x": 3, "y": 5, "color": "red"}, {"x": 4, "y": 5, "color": "red"}]}`))
+	if assert.IsType(t, DotErrors{}, err) {
+		assert.Equal(t, DotOutsideBoard, err.(DotErrors)[0].Kind)
+		assert.Equal(t, 1, err.(DotErrors)[0].Index)
+	}
+}
+
+// ringJson is a level of a 4x4 board with the 4 squares in the middle
+// blocked: the paths go around the ring left.
+var ringJson = []byte(`{"size": 4,
+	"blocked": [{"x": 1, "y": 1}, {"x": 2, "y": 1}, {"x": 1, "y": 2}, {"x": 2, "y": 2}],
+	"dots": [
+	{"x": 0, "y": 0, "color": "red"}, {"x": 3, "y": 0, "color": "red"},
+	{"x": 0, "y": 1, "color": "blue"}, {"x": 3, "y": 1, "color": "blue"}]}`)
+
+func TestLoadShape(t *testing.T) {
+	l, err := Load(ringJson)
+	assert.Nil(t, err)
+	assert.Len(t, l.Blocked, 4)
+	assert.True(t, l.IsBlocked(Coordinate{2, 1}))
+	assert.False(t, l.IsBlocked(Coordinate{3, 1}))
+
+	l.Walls = []Wall{{Coordinate{0, 0}, Coordinate{0, 1}}}
+	data, err := l.Marshal()
+	assert.Nil(t, err)
+	m, err := Load(data)
+	assert.Nil(t, err)
+	assert.Equal(t, l, m)
+	assert.True(t, l.SameShape(m))
+
+	for _, json := range []string{
+		`{"size": 4, "blocked": [{"x": 4, "y": 0}], "dots": [{"x": 0, "y": 0, "color": "red"}, {"x": 1, "y": 0, "color": "red"}]}`,
+		`{"size": 4, "blocked": [{"x": 2, "y": 2}, {"x": 2, "y": 2}], "dots": [{"x": 0, "y": 0, "color": "red"}, {"x": 1, "y": 0, "color": "red"}]}`,
+		`{"size": 4, "walls": [{"from": {"x": 0, "y": 0}, "to": {"x": 1, "y": 1}}], "dots": [{"x": 0, "y": 0, "color": "red"}, {"x": 1, "y": 0, "color": "red"}]}`,
+	} {
+		_, err = Load([]byte(json))
+		assert.NotNil(t, err, json)
+	}
+
+	_, err = Load([]byte(`{"size": 4, "blocked": [{"x": 1, "y": 0}], "dots": [
+		{"x": 0, "y": 0, "color": "red"}, {"x": 1, "y": 0, "color": "red"}]}`))
+	if assert.IsType(t, DotErrors{}, err) {
+		assert.Equal(t, BlockedDot, err.(DotErrors)[0].Kind)
+	}
+}
+
+func TestSameDots(t *testing.T) {
+	l, err := Load(ringJson)
+	assert.Nil(t, err)
+
+	m, err := Load(ringJson)
+	assert.Nil(t, err)
+	m.Dots[0], m.Dots[3] = m.Dots[3], m.Dots[0]
+	assert.True(t, l.SameDots(m))
+
+	m.Dots[0].Color = graphics.Green
+	assert.False(t, l.SameDots(m))
+
+	m, err = Load(ringJson)
+	assert.Nil(t, err)
+	m.Walls = []Wall{{Coordinate{0, 0}, Coordinate{0, 1}}}
+	assert.False(t, l.SameDots(m))
+}
@@ -0,0 +1,255 @@
+package game
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Progress reports the state of a running solve.
+type Progress struct {
+	// The number of search nodes explored so far (by all the workers).
+	Nodes int64
+
+	// The number of lines drawn in the search state
+	// reported most recently by a worker.
+	Depth int
+}
+
+// counters are the search statistics shared by the workers.
+type counters struct {
+	nodes int64
+	depth int64
+}
+
+func (c *counters) progress() Progress {
+	return Progress{
+		Nodes: atomic.LoadInt64(&c.nodes),
+		Depth: int(atomic.LoadInt64(&c.depth)),
+	}
+}
+
+type solveConfig struct {
+	// The number of worker goroutines.
+	workers int
+
+	// The function called periodically with the solve progress.
+	progress func(Progress)
+
+	// The interval between two progress reports.
+	interval time.Duration
+
+	// The paths (drawn beforehand) the solution must extend.
+	fixed []*Path
+}
+
+// SolveOption configures SolveContext.
+type SolveOption func(*solveConfig)
+
+// WithWorkers sets the number of the worker goroutines
+// (the number of CPUs by default).
+func WithWorkers(n int) SolveOption {
+	return func(c *solveConfig) {
+		if n > 0 {
+			c.workers = n
+		}
+	}
+}
+
+// WithProgress sets a function which is called periodically (from a
+// goroutine of its own) with the progress of the solve, and once more
+// when the solve ends.
+func WithProgress(f func(Progress), interval time.Duration) SolveOption {
+	return func(c *solveConfig) {
+		c.progress = f
+		if interval > 0 {
+			c.interval = interval
+		}
+	}
+}
+
+// WithFixedPaths sets the paths (e.g. drawn by the player) the solution
+// must keep: each of them is either complete or a prefix of a solution path.
+func WithFixedPaths(paths []*Path) SolveOption {
+	return func(c *solveConfig) {
+		c.fixed = paths
+	}
+}
+
+// move extends the path of a pair by one square.
+type move struct {
+	pair   int
+	square int
+}
+
+// split enumerates the feasible states reached after the first branching
+// decisions until there are at least n of them (or the search tree is
+// exhausted). Each state is given as the moves leading to it from the root.
+// It returns no state once the context is cancelled.
+func (s *solver) split(ctx context.Context, n int) [][]move {
+	var jobs [][]move
+	for depth := 1; ; depth++ {
+		jobs = jobs[:0]
+		exhausted := true
+		s.frontier(ctx, depth, nil, &jobs, &exhausted)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if len(jobs) >= n || exhausted {
+			return jobs
+		}
+	}
+}
+
+// frontier collects the states found at a given depth below the current
+// state, as well as the complete states found above it. It gives up
+// once the context is cancelled.
+func (s *solver) frontier(ctx context.Context, depth int, prefix []move, jobs *[][]move, exhausted *bool) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	p, moves := s.choose(make([]int, 0, 4))
+	if p < 0 || depth == 0 {
+		if p >= 0 {
+			*exhausted = false
+		}
+		*jobs = append(*jobs, append([]move(nil), prefix...))
+		return
+	}
+
+	for _, sq := range moves {
+		s.extend(p, sq)
+		if s.feasible() {
+			s.frontier(ctx, depth-1, append(prefix, move{p, sq}), jobs, exhausted)
+		}
+		s.retract(p)
+	}
+}
+
+// SolveContext finds a solution for a level like Solve does, but the search
+// is split on its first branching decisions and run by a pool of worker
+// goroutines. The solve stops when a worker finds a solution or the context
+// is cancelled; in the latter case the context error is returned.
+func SolveContext(ctx context.Context, level *Level, opts ...SolveOption) ([]*Path, error) {
+	cfg := solveConfig{
+		workers:  runtime.NumCPU(),
+		interval: 100 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	root, err := newSolver(level)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := root.fix(cfg.fixed); err != nil {
+		return nil, err
+	}
+
+	if !root.feasible() {
+		return nil, ErrNoSolution
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	shared := &counters{}
+	jobs := make(chan []move)
+	found := make(chan []*Path, 1)
+
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.workers; w++ {
+		s, _ := newSolver(level)
+		s.fix(cfg.fixed) //nolint
+		s.ctx = ctx
+		s.shared = shared
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				for _, m := range job {
+					s.extend(m.pair, m.square)
+				}
+
+				stopped := s.search(func() bool {
+					select {
+					case found <- s.paths():
+					default:
+					}
+					cancel()
+					return true
+				})
+				s.publish()
+				if stopped {
+					return
+				}
+
+				for i := len(job) - 1; i >= 0; i-- {
+					s.retract(job[i].pair)
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	reporter := make(chan struct{})
+	if cfg.progress != nil {
+		go func() {
+			defer close(reporter)
+			t := time.NewTicker(cfg.interval)
+			defer t.Stop()
+			for {
+				select {
+				case <-t.C:
+					cfg.progress(shared.progress())
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, job := range root.split(ctx, 4*cfg.workers) {
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case jobs <- job:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+	close(done)
+
+	// the workers stopped: drain the jobs left (if any) so the feeder ends
+	for range jobs {
+	}
+
+	if cfg.progress != nil {
+		<-reporter
+		cfg.progress(shared.progress())
+	}
+
+	select {
+	case paths := <-found:
+		return paths, nil
+	default:
+	}
+
+	// the parent context error (the local cancel only follows a solution)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return nil, ErrNoSolution
+}
@@ -0,0 +1,225 @@
+package game
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// partition is a covering of a board by paths: the solution
+// of a generated level whose dots are the ends of the paths.
+// The squares are indexed the same way the Board does: x*size+y.
+type partition struct {
+	// The size of the board.
+	size int32
+
+	// The neighbours (orthogonal adjacent squares) of each square.
+	adj [][]int
+
+	// The squares of each path.
+	paths [][]int
+
+	// The path covering each square and the position of the square
+	// in the path.
+	owner []int
+	pos   []int
+
+	// The bounds of the lengths of the paths (no maximum if 0).
+	minLength int
+	maxLength int
+
+	// For the symmetric layouts, the paths cover a half of the board
+	// only: image maps a square to its image by the symmetry (nil if
+	// there is no symmetry), and the fixed paths cover the squares
+	// left, on the axis of the symmetry.
+	image func(int) int
+	fixed [][]int
+}
+
+// newPartition cuts a path into pieces of the given lengths,
+// which must stay between min and max (no maximum if 0).
+func newPartition(size int32, path []int, lengths []int, min, max int) *partition {
+	n := int(size * size)
+	p := &partition{
+		size:      size,
+		adj:       neighbours(size, size),
+		owner:     make([]int, n),
+		pos:       make([]int, n),
+		minLength: min,
+		maxLength: max,
+	}
+
+	start := 0
+	for i, length := range lengths {
+		p.paths = append(p.paths, append([]int(nil), path[start:start+length]...))
+		p.reindex(i)
+		start += length
+	}
+
+	return p
+}
+
+// reindex updates the owner and the position of the squares of a path.
+func (p *partition) reindex(i int) {
+	for j, sq := range p.paths[i] {
+		p.owner[sq] = i
+		p.pos[sq] = j
+	}
+}
+
+func reverseSquares(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// backbite reshapes the paths at random: the end of a path is joined to
+// an adjacent square and the paths are rearranged so that they still
+// cover the board:
+// - if the square belongs to the same path, the part of the path
+// between the square and the end is reversed
+// - otherwise the path takes over the part of the other path on one
+// side of the square.
+//
+// It reports whether the paths changed (the moves leaving a path
+// shorter or longer than the bounds of the lengths are rejected).
+func (p *partition) backbite(r *rand.Rand) bool {
+	a := r.Intn(len(p.paths))
+	if r.Intn(2) == 0 {
+		reverseSquares(p.paths[a])
+		p.reindex(a)
+	}
+
+	pa := p.paths[a]
+	tail := pa[len(pa)-1]
+	sq := p.adj[tail][r.Intn(len(p.adj[tail]))]
+	b, j := p.owner[sq], p.pos[sq]
+
+	if b == a {
+		if j == len(pa)-2 {
+			return false
+		}
+		reverseSquares(pa[j+1:])
+		p.reindex(a)
+		return true
+	}
+
+	pb := p.paths[b]
+	var taken, left []int
+	if r.Intn(2) == 0 {
+		taken, left = pb[j:], pb[:j]
+	} else {
+		taken, left = append([]int(nil), pb[:j+1]...), pb[j+1:]
+		reverseSquares(taken)
+	}
+	if len(left) < p.minLength || (p.maxLength > 0 && len(pa)+len(taken) > p.maxLength) {
+		return false
+	}
+
+	p.paths[a] = append(append([]int(nil), pa...), taken...)
+	p.paths[b] = append([]int(nil), left...)
+	p.reindex(a)
+	p.reindex(b)
+	return true
+}
+
+// touches counts the pairs of adjacent squares covered by the same path
+// which do not follow each other on the path. Such a path may take a
+// shortcut, so the levels having few touches tend to have a unique
+// solution.
+func (p *partition) touches() int {
+	count := 0
+	for sq, ns := range p.adj {
+		for _, n := range ns {
+			if n < sq || p.owner[n] != p.owner[sq] {
+				continue
+			}
+			if d := p.pos[n] - p.pos[sq]; d != 1 && d != -1 {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// clone returns a copy of the partition.
+func (p *partition) clone() *partition {
+	c := &partition{
+		size:      p.size,
+		adj:       p.adj,
+		owner:     append([]int(nil), p.owner...),
+		pos:       append([]int(nil), p.pos...),
+		minLength: p.minLength,
+		maxLength: p.maxLength,
+		image:     p.image,
+		fixed:     p.fixed,
+	}
+	for _, path := range p.paths {
+		c.paths = append(c.paths, append([]int(nil), path...))
+	}
+	return c
+}
+
+// untangle reshapes the paths at random, keeping the changes which do
+// not increase the number of touches, until no path touches itself or
+// the number of moves is reached.
+func (p *partition) untangle(r *rand.Rand, moves int) *partition {
+	best, score := p, p.touches()
+	for m := 0; m < moves && score > 0; m++ {
+		c := best.clone()
+		if !c.backbite(r) {
+			continue
+		}
+		if s := c.touches(); s <= score {
+			best, score = c, s
+		}
+	}
+	return best
+}
+
+// all returns the paths covering the whole board: the paths, their images
+// by the symmetry and the fixed paths.
+func (p *partition) all() [][]int {
+	if p.image == nil {
+		return p.paths
+	}
+
+	all := append([][]int(nil), p.paths...)
+	for _, path := range p.paths {
+		image := make([]int, len(path))
+		for i, sq := range path {
+			image[i] = p.image(sq)
+		}
+		all = append(all, image)
+	}
+	return append(all, p.fixed...)
+}
+
+// level returns the level having the dots at the ends of the paths
+// and its solution.
+func (p *partition) level() (*Level, []*Path) {
+	coord := func(sq int) Coordinate {
+		return Coordinate{int32(sq) / p.size, int32(sq) % p.size}
+	}
+
+	l := &Level{Width: p.size, Height: p.size}
+	var solution []*Path
+	for i, path := range p.all() {
+		clr := palette[i]
+		first, last := Dot{coord(path[0]), clr}, Dot{coord(path[len(path)-1]), clr}
+		l.Dots = append(l.Dots, first, last)
+
+		sp := &Path{StartDot: &first, EndDot: &last}
+		for j := 1; j < len(path); j++ {
+			sp.AddLine(coord(path[j-1]), coord(path[j]))
+		}
+		solution = append(solution, sp)
+	}
+
+	// the dots are listed row by row, like in the level files
+	sort.Slice(l.Dots, func(i, j int) bool {
+		a, b := l.Dots[i].Location, l.Dots[j].Location
+		return a.Y < b.Y || (a.Y == b.Y && a.X < b.X)
+	})
+
+	return l, solution
+}
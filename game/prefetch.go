@@ -0,0 +1,167 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// prefetchLevels is the number of the upcoming levels kept ready.
+const prefetchLevels = 3
+
+// upcoming is a level ready to be played.
+type upcoming struct {
+	level *Level
+
+	// The name of the level file, or endless-N for the N-th generated level.
+	file string
+
+	// The parameters the level was generated with (nil for the level files).
+	generated *generated
+
+	// The error which ended the levels (if any).
+	err error
+}
+
+// levelSource produces the levels following a level file: the next level
+// files (the bigger boards after the smaller ones), then the levels of the
+// endless mode.
+type levelSource struct {
+	// The directory of the game (holding the data directory).
+	dir string
+
+	// The size of the board and the number of the next level file.
+	size int32
+	next int
+
+	// The seed of the endless mode.
+	seed int64
+
+	// The endless mode, once the level files have run out.
+	endless *endless
+}
+
+// newLevelSource returns the source of the levels following the given
+// level file (a file name such as 3.json, for a board of the given size).
+func newLevelSource(dir, file string, size int32, seed int64) (*levelSource, error) {
+	s := strings.Split(file, ".")
+	if len(s) != 2 {
+		return nil, fmt.Errorf("Wrong file name for the game level: %s", file)
+	}
+
+	n, err := strconv.Atoi(s[0])
+	if err != nil {
+		return nil, fmt.Errorf("Wrong file name for the game level: %s", file)
+	}
+
+	return &levelSource{dir: dir, size: size, next: n + 1, seed: seed}, nil
+}
+
+// nextLevel loads the next level file, or generates the next level once
+// there are no more files to load the levels from.
+func (s *levelSource) nextLevel(ctx context.Context) upcoming {
+	if s.endless == nil {
+		if u, ok := s.nextLevelFile(); ok {
+			return u
+		}
+		s.endless = newEndless(s.seed)
+	}
+
+	l, err := s.endless.next(ctx)
+	if err != nil {
+		return upcoming{err: err}
+	}
+
+	cur := s.endless.current()
+	l.Name = fmt.Sprintf("Endless #%d", len(s.endless.levels))
+	return upcoming{
+		level:     l,
+		file:      fmt.Sprintf("endless-%d", len(s.endless.levels)),
+		generated: &cur,
+	}
+}
+
+// nextLevelFile loads the next level file. It returns false if there are
+// no more files to load the levels from.
+func (s *levelSource) nextLevelFile() (upcoming, bool) {
+	for {
+		filePath := fmt.Sprintf("%s/data/%d/%d.json", s.dir, s.size, s.next)
+		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			if s.size >= maxBoardSize {
+				return upcoming{}, false
+			}
+			s.size++
+			s.next = 0
+			continue
+		}
+
+		file := fmt.Sprintf("%d.json", s.next)
+		s.next++
+
+		l, err := LoadFromFile(filePath)
+		if err != nil {
+			err = fmt.Errorf("Failed to load the level from file %s: %v", filePath, err)
+		}
+		return upcoming{level: l, file: file, err: err}, true
+	}
+}
+
+// prefetch is a producer goroutine keeping the upcoming levels ready
+// in a bounded queue, so moving on to the next level does not wait for
+// the level to be loaded or generated.
+type prefetch struct {
+	cancel context.CancelFunc
+
+	// The queue of the upcoming levels (closed when the producer ends).
+	levels chan upcoming
+}
+
+// startPrefetch starts producing the levels of the source in the background.
+// The producer ends after the first error.
+func startPrefetch(src *levelSource) *prefetch {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &prefetch{
+		cancel: cancel,
+		levels: make(chan upcoming, prefetchLevels),
+	}
+
+	go func() {
+		defer close(p.levels)
+		for {
+			u := src.nextLevel(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+
+			select {
+			case p.levels <- u:
+			case <-ctx.Done():
+				return
+			}
+
+			if u.err != nil {
+				return
+			}
+		}
+	}()
+
+	return p
+}
+
+// next returns the next level (waiting for it if it is not ready yet).
+func (p *prefetch) next() upcoming {
+	u, ok := <-p.levels
+	if !ok {
+		return upcoming{err: fmt.Errorf("No more levels")}
+	}
+	return u
+}
+
+// stop cancels the producer and waits for its goroutine to end.
+func (p *prefetch) stop() {
+	p.cancel()
+	for range p.levels {
+	}
+}
@@ -0,0 +1,57 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefetch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "prefetch")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// the next size is the last one
+	size := int32(maxBoardSize - 1)
+	l, err := Generate(size, 5, 1)
+	assert.Nil(t, err)
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "data", fmt.Sprint(size)), 0755))
+	assert.Nil(t, l.SaveToFile(filepath.Join(dir, "data", fmt.Sprint(size), "1.json")))
+
+	src, err := newLevelSource(dir, "0.json", size, 42)
+	assert.Nil(t, err)
+
+	p := startPrefetch(src)
+	defer p.stop()
+
+	// the level files come first
+	u := p.next()
+	assert.Nil(t, u.err)
+	assert.Equal(t, "1.json", u.file)
+	assert.Nil(t, u.generated)
+	assert.Equal(t, l, u.level)
+
+	// then the endless mode
+	e := newEndless(42)
+	for i := 1; i <= 2; i++ {
+		u = p.next()
+		assert.Nil(t, u.err)
+		assert.Equal(t, fmt.Sprintf("endless-%d", i), u.file)
+
+		expected, err := e.next(context.Background())
+		assert.Nil(t, err)
+		expected.Name = fmt.Sprintf("Endless #%d", i)
+		assert.Equal(t, expected, u.level)
+		assert.Equal(t, e.current(), *u.generated)
+	}
+}
+
+func TestLevelSourceInvalid(t *testing.T) {
+	_, err := newLevelSource(".", "endless", 5, 0)
+	assert.Error(t, err)
+}
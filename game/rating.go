@@ -0,0 +1,88 @@
+package game
+
+import "fmt"
+
+// Difficulty is the grade of a level, given by the hardest deduction
+// technique a player needs in order to solve it.
+type Difficulty int32
+
+const (
+	// Only forced moves are needed.
+	Easy Difficulty = iota
+	// The corner rule or the dead cells avoidance are needed.
+	Medium
+	// The bottleneck reasoning is needed.
+	Hard
+	// The deduction techniques are not enough: some moves must be guessed.
+	Expert
+)
+
+var difficultyNames = []string{
+	"easy",
+	"medium",
+	"hard",
+	"expert",
+}
+
+// String returns the name of the difficulty.
+func (d Difficulty) String() string {
+	if d.IsValid() {
+		return difficultyNames[d]
+	}
+	return fmt.Sprintf("difficulty %d", int32(d))
+}
+
+// IsValid checks if the difficulty is one of the known grades.
+func (d Difficulty) IsValid() bool {
+	return d >= Easy && d <= Expert
+}
+
+// Grade returns the difficulty of the levels requiring the technique.
+func (t Technique) Grade() Difficulty {
+	switch t {
+	case ForcedMove:
+		return Easy
+	case CornerRule, DeadCell:
+		return Medium
+	default:
+		return Hard
+	}
+}
+
+// Rate computes the difficulty of a level: the level is solved by
+// applying the easiest technique which finds a move, over and over, and
+// the grade is given by the hardest technique used. The levels where the
+// techniques get stuck are rated Expert (if they have a solution at all).
+func Rate(level *Level) (Difficulty, error) {
+	d, err := newDeducer(level)
+	if err != nil {
+		return Easy, err
+	}
+
+	grade, solved := d.grade()
+	if !solved {
+		if _, err := Solve(level); err != nil {
+			return Easy, err
+		}
+	}
+	return grade, nil
+}
+
+// grade solves the level with the deduction techniques and returns the
+// grade of the hardest technique used, or Expert if the techniques get
+// stuck (in which case it also reports the level was not solved).
+func (d *deducer) grade() (Difficulty, bool) {
+	grade := Easy
+	for !d.solved() {
+		ded, ok := d.next()
+		if !ok {
+			return Expert, false
+		}
+
+		if g := ded.Technique.Grade(); g > grade {
+			grade = g
+		}
+		d.apply(ded)
+	}
+	return grade, true
+}
@@ -0,0 +1,66 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// levelOf loads a level from its size and the JSON array of its dots.
+func levelOf(t *testing.T, size int32, dots string) *Level {
+	l, err := Load([]byte(fmt.Sprintf(`{"size": %d, "dots": %s}`, size, dots)))
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return l
+}
+
+func TestRate(t *testing.T) {
+	tests := []struct {
+		size       int32
+		dots       string
+		difficulty Difficulty
+	}{
+		{5, `[{"x":0,"y":0,"color":"red"},{"x":2,"y":0,"color":"yellow"},{"x":3,"y":0,"color":"green"},
+			{"x":4,"y":0,"color":"orange"},{"x":3,"y":2,"color":"blue"},{"x":1,"y":3,"color":"yellow"},
+			{"x":2,"y":3,"color":"green"},{"x":4,"y":3,"color":"orange"},{"x":2,"y":4,"color":"red"},
+			{"x":4,"y":4,"color":"blue"}]`, Easy},
+		{5, `[{"x":1,"y":1,"color":"red"},{"x":0,"y":0,"color":"red"},{"x":0,"y":1,"color":"green"},
+			{"x":0,"y":2,"color":"green"},{"x":1,"y":4,"color":"blue"},{"x":4,"y":2,"color":"blue"},
+			{"x":3,"y":1,"color":"yellow"},{"x":4,"y":3,"color":"yellow"}]`, Medium},
+		{5, `[{"x":2,"y":0,"color":"red"},{"x":2,"y":1,"color":"red"},{"x":3,"y":1,"color":"green"},
+			{"x":0,"y":4,"color":"green"},{"x":0,"y":1,"color":"blue"},{"x":1,"y":1,"color":"blue"},
+			{"x":3,"y":4,"color":"yellow"},{"x":1,"y":3,"color":"yellow"}]`, Hard},
+		{6, `[{"x":4,"y":4,"color":"red"},{"x":1,"y":3,"color":"red"},{"x":0,"y":2,"color":"green"},
+			{"x":3,"y":4,"color":"green"},{"x":2,"y":5,"color":"blue"},{"x":5,"y":2,"color":"blue"},
+			{"x":4,"y":1,"color":"yellow"},{"x":2,"y":2,"color":"yellow"}]`, Expert},
+	}
+
+	for _, tt := range tests {
+		d, err := Rate(levelOf(t, tt.size, tt.dots))
+		assert.Nil(t, err)
+		assert.Equal(t, tt.difficulty, d)
+	}
+}
+
+func TestRateNoSolution(t *testing.T) {
+	l, err := Load(blobJson)
+	assert.Nil(t, err)
+
+	_, err = Rate(l)
+	assert.Equal(t, ErrNoSolution, err)
+}
+
+func TestMarshal(t *testing.T) {
+	l, err := Load(blobJson)
+	assert.Nil(t, err)
+	l.Difficulty = Hard
+
+	data, err := l.Marshal()
+	assert.Nil(t, err)
+
+	m, err := Load(data)
+	assert.Nil(t, err)
+	assert.Equal(t, l, m)
+}
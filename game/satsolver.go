@@ -0,0 +1,372 @@
+package game
+
+import (
+	"connect-dots/sat"
+	"context"
+	"fmt"
+	"io"
+)
+
+// satEncoding maps a level onto the variables and the clauses
+// of a SAT problem:
+// - a color variable for each (square, color): the square is covered
+// by the path of that color
+// - an edge variable for each pair of adjacent squares: a line connects
+// the two squares.
+//
+// The clauses state that every square has exactly one color, the dots have
+// their own color, a dot has exactly one line and any other square has
+// exactly two lines (so the board is fully covered) and the squares
+// connected by a line have the same color.
+//
+// Such a model may still contain loops detached from the paths; these are
+// ruled out lazily, by blocking each loop found and solving again.
+type satEncoding struct {
+	width  int32
+	height int32
+	pairs  []pair
+	adj    [][]int
+
+	// The squares which cannot be entered (they have no color).
+	blocked []bool
+
+	solver *sat.Solver
+
+	// The color variables: colors[square][pair].
+	colors [][]int
+
+	// The edge variables indexed by the (ordered) adjacent squares.
+	edges map[[2]int]int
+}
+
+func newSATEncoding(level *Level) (*satEncoding, error) {
+	pairs, err := pairsOf(level)
+	if err != nil {
+		return nil, err
+	}
+
+	n := int(level.Width * level.Height)
+	e := &satEncoding{
+		width:   level.Width,
+		height:  level.Height,
+		pairs:   pairs,
+		adj:     levelNeighbours(level),
+		solver:  sat.New(),
+		colors:  make([][]int, n),
+		blocked: make([]bool, n),
+		edges:   make(map[[2]int]int),
+	}
+
+	s := e.solver
+	for i := range e.colors {
+		e.colors[i] = make([]int, len(pairs))
+		for k := range pairs {
+			e.colors[i][k] = s.NewVar()
+		}
+	}
+
+	for i, ns := range e.adj {
+		for _, j := range ns {
+			if i < j {
+				e.edges[[2]int{i, j}] = s.NewVar()
+			}
+		}
+	}
+
+	dots := make(map[int]int)
+	for k, p := range pairs {
+		dots[int(p.start.Location.X*e.height+p.start.Location.Y)] = k
+		dots[int(p.end.Location.X*e.height+p.end.Location.Y)] = k
+	}
+
+	for _, c := range level.Blocked {
+		e.blocked[int(c.X*e.height+c.Y)] = true
+	}
+
+	for i := range e.colors {
+		if e.blocked[i] {
+			continue
+		}
+
+		// exactly one color per square
+		s.AddClause(e.colors[i]...)
+		for a := 0; a < len(pairs); a++ {
+			for b := a + 1; b < len(pairs); b++ {
+				s.AddClause(-e.colors[i][a], -e.colors[i][b])
+			}
+		}
+
+		lines := make([]int, 0, 4)
+		for _, j := range e.adj[i] {
+			lines = append(lines, e.edge(i, j))
+		}
+
+		if k, ok := dots[i]; ok {
+			s.AddClause(e.colors[i][k])
+			exactly(s, lines, 1)
+		} else {
+			exactly(s, lines, 2)
+		}
+
+		// the squares connected by a line have the same color
+		for _, j := range e.adj[i] {
+			if i > j {
+				continue
+			}
+			l := e.edge(i, j)
+			for k := range pairs {
+				s.AddClause(-l, -e.colors[i][k], e.colors[j][k])
+				s.AddClause(-l, -e.colors[j][k], e.colors[i][k])
+			}
+		}
+	}
+
+	// the smallest loops are ruled out upfront
+	for x := int32(0); x < e.width-1; x++ {
+		for y := int32(0); y < e.height-1; y++ {
+			a := int(x*e.height + y)
+			b, c, d := a+1, a+int(e.height), a+int(e.height)+1
+			if e.edge(a, b) == 0 || e.edge(a, c) == 0 || e.edge(b, d) == 0 || e.edge(c, d) == 0 {
+				// a blocked square or a wall: no loop
+				continue
+			}
+			s.AddClause(-e.edge(a, b), -e.edge(a, c), -e.edge(b, d), -e.edge(c, d))
+		}
+	}
+
+	return e, nil
+}
+
+// exactly adds the clauses stating that exactly k (1 or 2)
+// of the variables are true.
+func exactly(s *sat.Solver, vars []int, k int) {
+	if len(vars) < k {
+		s.AddClause()
+		return
+	}
+
+	// at least k: any len(vars)-k+1 variables contain a true one
+	subsets(len(vars), len(vars)-k+1, func(idx []int) {
+		c := make([]int, len(idx))
+		for i, j := range idx {
+			c[i] = vars[j]
+		}
+		s.AddClause(c...)
+	})
+
+	// at most k: any k+1 variables contain a false one
+	subsets(len(vars), k+1, func(idx []int) {
+		c := make([]int, len(idx))
+		for i, j := range idx {
+			c[i] = -vars[j]
+		}
+		s.AddClause(c...)
+	})
+}
+
+// subsets calls f for each subset of size k of {0..n-1}.
+func subsets(n, k int, f func([]int)) {
+	if k > n || k <= 0 {
+		return
+	}
+
+	idx := make([]int, k)
+	for i := range idx {
+		idx[i] = i
+	}
+
+	for {
+		f(idx)
+
+		i := k - 1
+		for i >= 0 && idx[i] == n-k+i {
+			i--
+		}
+		if i < 0 {
+			return
+		}
+		idx[i]++
+		for j := i + 1; j < k; j++ {
+			idx[j] = idx[j-1] + 1
+		}
+	}
+}
+
+func (e *satEncoding) edge(i, j int) int {
+	if i > j {
+		i, j = j, i
+	}
+	return e.edges[[2]int{i, j}]
+}
+
+func (e *satEncoding) coord(i int) Coordinate {
+	return Coordinate{int32(i) / e.height, int32(i) % e.height}
+}
+
+// linked returns the squares connected by a line to the square i
+// in the current model.
+func (e *satEncoding) linked(i int) []int {
+	var ls []int
+	for _, j := range e.adj[i] {
+		if e.solver.Value(e.edge(i, j)) {
+			ls = append(ls, j)
+		}
+	}
+	return ls
+}
+
+// decode follows the lines of the current model from the start dots.
+// It returns the paths and the loops (the squares not reached by any path).
+func (e *satEncoding) decode() ([]*Path, [][]int) {
+	visited := make([]bool, len(e.colors))
+	copy(visited, e.blocked)
+
+	paths := make([]*Path, len(e.pairs))
+	for k, p := range e.pairs {
+		start, end := p.start, p.end
+		path := &Path{StartDot: &start, EndDot: &end}
+
+		prev, crt := -1, int(start.Location.X*e.height+start.Location.Y)
+		visited[crt] = true
+		for {
+			next := -1
+			for _, j := range e.linked(crt) {
+				if j != prev {
+					next = j
+				}
+			}
+			if next < 0 {
+				break
+			}
+			path.AddLine(e.coord(crt), e.coord(next))
+			visited[next] = true
+			prev, crt = crt, next
+		}
+		paths[k] = path
+	}
+
+	var loops [][]int
+	for i := range visited {
+		if visited[i] {
+			continue
+		}
+
+		loop := []int{i}
+		visited[i] = true
+		prev, crt := -1, i
+		for {
+			next := -1
+			for _, j := range e.linked(crt) {
+				if j != prev && !visited[j] {
+					next = j
+					break
+				}
+			}
+			if next < 0 {
+				break
+			}
+			loop = append(loop, next)
+			visited[next] = true
+			prev, crt = crt, next
+		}
+		loops = append(loops, loop)
+	}
+
+	return paths, loops
+}
+
+// blockLoop forbids a loop: at least one of its lines must be missing.
+func (e *satEncoding) blockLoop(loop []int) {
+	c := make([]int, 0, len(loop))
+	for i := range loop {
+		c = append(c, -e.edge(loop[i], loop[(i+1)%len(loop)]))
+	}
+	e.solver.AddClause(c...)
+}
+
+// SolveSAT finds a solution for a level by encoding it as a SAT problem.
+// It is the backend of choice for the large boards, where the backtracking
+// search of Solve becomes too slow.
+func SolveSAT(level *Level) ([]*Path, error) {
+	return SolveSATContext(context.Background(), level)
+}
+
+// SolveSATContext is like SolveSAT but it gives up when the context
+// is cancelled, returning the context error.
+func SolveSATContext(ctx context.Context, level *Level) ([]*Path, error) {
+	e, err := newSATEncoding(level)
+	if err != nil {
+		return nil, err
+	}
+
+	return e.solve(ctx)
+}
+
+// solve runs the SAT solver until it finds a model without loops.
+func (e *satEncoding) solve(ctx context.Context) ([]*Path, error) {
+	for {
+		ok, err := e.solver.SolveContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrNoSolution
+		}
+
+		paths, loops := e.decode()
+		if len(loops) == 0 {
+			return paths, nil
+		}
+
+		for _, loop := range loops {
+			e.blockLoop(loop)
+		}
+	}
+}
+
+// blockSolution forbids a solution: at least one of its lines must be
+// missing. The solutions covering the whole board have the same number
+// of lines, so any other solution misses one of them.
+func (e *satEncoding) blockSolution(paths []*Path) {
+	var c []int
+	for _, p := range paths {
+		for _, l := range p.Lines {
+			c = append(c, -e.edge(int(l.From.X*e.height+l.From.Y), int(l.To.X*e.height+l.To.Y)))
+		}
+	}
+	e.solver.AddClause(c...)
+}
+
+// otherSolution looks for a solution of a level which differs from
+// the given one. It returns nil (and no error) if the solution is unique.
+func otherSolution(ctx context.Context, level *Level, paths []*Path) ([]*Path, error) {
+	e, err := newSATEncoding(level)
+	if err != nil {
+		return nil, err
+	}
+
+	e.blockSolution(paths)
+	other, err := e.solve(ctx)
+	if err == ErrNoSolution {
+		return nil, nil
+	}
+	return other, err
+}
+
+// WriteDIMACS writes the SAT encoding of a level in the DIMACS CNF format,
+// for cross-checking it with reference solvers.
+// The loops detached from the paths are ruled out lazily by SolveSAT, so the
+// clauses only forbid the 2x2 loops: an external solver may return a model
+// with longer loops.
+func WriteDIMACS(w io.Writer, level *Level) error {
+	e, err := newSATEncoding(level)
+	if err != nil {
+		return err
+	}
+
+	return e.solver.WriteDIMACS(w,
+		fmt.Sprintf("connect-dots level: %dx%d, %d colors", level.Width, level.Height, len(e.pairs)),
+		fmt.Sprintf("variables 1..%d: square*%d+color+1", len(e.colors)*len(e.pairs), len(e.pairs)),
+		"the remaining variables: the lines between adjacent squares",
+	)
+}
@@ -0,0 +1,396 @@
+package game
+
+import (
+	"connect-dots/graphics"
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrNoSolution is returned when a level cannot be completed.
+var ErrNoSolution = errors.New("The level has no solution")
+
+// free marks a board square which is not covered by any path.
+const free = -1
+
+// blockedSquare marks a board square which cannot be entered
+// (it has no neighbours, see levelNeighbours).
+const blockedSquare = -2
+
+// pair stores the two dots having the same color.
+type pair struct {
+	// The color of the dots.
+	color graphics.Color
+	// The dot the path starts from.
+	start Dot
+	// The dot the path ends at.
+	end Dot
+}
+
+// pairsOf groups the dots of a level by color.
+// Each color must appear exactly twice and the dots must be
+// placed on distinct squares inside the board.
+func pairsOf(level *Level) ([]pair, error) {
+	if level.Width <= 0 {
+		return nil, fmt.Errorf("Invalid value for width: %d", level.Width)
+	}
+	if level.Height <= 0 {
+		return nil, fmt.Errorf("Invalid value for height: %d", level.Height)
+	}
+
+	if len(level.Dots) == 0 {
+		return nil, errors.New("No dots found in the level")
+	}
+
+	var pairs []pair
+	index := make(map[graphics.Color]int)
+	seen := make(map[Coordinate]bool)
+	for _, dot := range level.Dots {
+		c := dot.Location
+		if c.X < 0 || c.Y < 0 || c.X >= level.Width || c.Y >= level.Height {
+			return nil, fmt.Errorf("Dot (%d,%d) is outside the board", c.X, c.Y)
+		}
+
+		if level.IsBlocked(c) {
+			return nil, fmt.Errorf("Dot (%d,%d) is on a blocked square", c.X, c.Y)
+		}
+
+		if seen[c] {
+			return nil, fmt.Errorf("Duplicate dot at (%d,%d)", c.X, c.Y)
+		}
+		seen[c] = true
+
+		i, ok := index[dot.Color]
+		if !ok {
+			index[dot.Color] = len(pairs)
+			pairs = append(pairs, pair{color: dot.Color, start: dot, end: Dot{Location: Coordinate{-1, -1}}})
+			continue
+		}
+
+		if pairs[i].end.Location.IsValid() {
+			return nil, fmt.Errorf("Color %s appears more than twice", dot.Color)
+		}
+		pairs[i].end = dot
+	}
+
+	for _, p := range pairs {
+		if !p.end.Location.IsValid() {
+			return nil, fmt.Errorf("Color %s has a single dot", p.color)
+		}
+	}
+
+	return pairs, nil
+}
+
+// neighbours returns the orthogonal adjacent squares of each square
+// of a board. The squares are indexed the same way the Board does:
+// x*height+y.
+func neighbours(width, height int32) [][]int {
+	adj := make([][]int, width*height)
+	for x := int32(0); x < width; x++ {
+		for y := int32(0); y < height; y++ {
+			i := x*height + y
+			if x > 0 {
+				adj[i] = append(adj[i], int(i-height))
+			}
+			if x < width-1 {
+				adj[i] = append(adj[i], int(i+height))
+			}
+			if y > 0 {
+				adj[i] = append(adj[i], int(i-1))
+			}
+			if y < height-1 {
+				adj[i] = append(adj[i], int(i+1))
+			}
+		}
+	}
+	return adj
+}
+
+// levelNeighbours returns the neighbours of each square of the board of
+// a level (see neighbours) a line may join: the blocked squares have no
+// neighbours and the walls separate their squares.
+func levelNeighbours(level *Level) [][]int {
+	adj := neighbours(level.Width, level.Height)
+	if len(level.Blocked) == 0 && len(level.Walls) == 0 {
+		return adj
+	}
+
+	index := func(c Coordinate) int {
+		return int(c.X*level.Height + c.Y)
+	}
+
+	cut := make(map[[2]int]bool)
+	for _, c := range level.Blocked {
+		i := index(c)
+		for _, n := range adj[i] {
+			cut[[2]int{i, n}], cut[[2]int{n, i}] = true, true
+		}
+	}
+	for _, w := range level.Walls {
+		a, b := index(w.From), index(w.To)
+		cut[[2]int{a, b}], cut[[2]int{b, a}] = true, true
+	}
+
+	for i, ns := range adj {
+		var open []int
+		for _, n := range ns {
+			if !cut[[2]int{i, n}] {
+				open = append(open, n)
+			}
+		}
+		adj[i] = open
+	}
+	return adj
+}
+
+// solver is a backtracking search which extends the paths from
+// their start dots until all the dots are connected and
+// all the squares are covered.
+type solver struct {
+	// The squares covered by the paths: the paths only grow from
+	// their start dots (side 0).
+	*cover
+
+	// The number of lines drawn so far.
+	depth int
+
+	// The number of search nodes explored so far.
+	nodes int64
+
+	// The number of the nodes where a path was extended and the number
+	// of the moves tried at these nodes (see Analysis.BranchingFactor).
+	branching int64
+	branches  int64
+
+	// The number of nodes already added to the shared counters.
+	reported int64
+
+	// The counters shared by the workers of a parallel solve (if any).
+	shared *counters
+
+	// The context which cancels the search (if any) and
+	// the error reported when it was cancelled.
+	ctx context.Context
+	err error
+}
+
+func newSolver(level *Level) (*solver, error) {
+	c, err := newCover(level)
+	if err != nil {
+		return nil, err
+	}
+	return &solver{cover: c}, nil
+}
+
+// extend moves the head of the path p to the square n.
+func (s *solver) extend(p, n int) {
+	s.cover.extend(p, 0, n)
+	s.depth++
+}
+
+// retract undoes the last extend of the path p.
+func (s *solver) retract(p int) {
+	s.cover.retract(p, 0)
+	s.depth--
+}
+
+// fix replays the lines of paths drawn beforehand, so the search only
+// explores their extensions. A path may start from either dot of its pair.
+func (s *solver) fix(paths []*Path) error {
+	for _, path := range paths {
+		p := -1
+		for i, pr := range s.pairs {
+			if pr.color == path.StartDot.Color {
+				p = i
+				break
+			}
+		}
+		if p < 0 {
+			return fmt.Errorf("No dots found for the path of color %s", path.StartDot.Color)
+		}
+
+		if len(s.trails[p][0]) != 1 {
+			return fmt.Errorf("Several paths of color %s", path.StartDot.Color)
+		}
+
+		start := s.index(path.StartDot.Location)
+		switch start {
+		case s.tip(p, 0):
+		case s.tip(p, 1):
+			pr := &s.pairs[p]
+			pr.start, pr.end = pr.end, pr.start
+			s.trails[p][0], s.trails[p][1] = s.trails[p][1], s.trails[p][0]
+		default:
+			return fmt.Errorf("The path of color %s does not start from a dot", path.StartDot.Color)
+		}
+
+		for _, l := range path.Lines {
+			from, to := s.index(l.From), s.index(l.To)
+			legal := from == s.tip(p, 0) && !s.done[p] &&
+				(s.grid[to] == free || to == s.tip(p, 1)) && s.adjacent(from, to)
+			if !legal {
+				return fmt.Errorf("Invalid line (%d,%d)-(%d,%d) of color %s",
+					l.From.X, l.From.Y, l.To.X, l.To.Y, path.StartDot.Color)
+			}
+			s.extend(p, to)
+		}
+	}
+	return nil
+}
+
+// choose returns the unfinished path having the fewest moves
+// or -1 if all the paths are completed.
+func (s *solver) choose(buf []int) (int, []int) {
+	best, bestMoves := -1, []int(nil)
+	for p := range s.pairs {
+		if s.done[p] {
+			continue
+		}
+
+		m := s.moves(p, 0, buf)
+		if best < 0 || len(m) < len(bestMoves) {
+			best = p
+			bestMoves = append(bestMoves[:0], m...)
+			if len(m) <= 1 {
+				break
+			}
+		}
+	}
+	return best, bestMoves
+}
+
+// checkInterval is the number of search nodes between two checks
+// of the solve context.
+const checkInterval = 1024
+
+// publish adds the search statistics to the shared counters (if any).
+func (s *solver) publish() {
+	if s.shared != nil {
+		atomic.AddInt64(&s.shared.nodes, s.nodes-s.reported)
+		atomic.StoreInt64(&s.shared.depth, int64(s.depth))
+		s.reported = s.nodes
+	}
+}
+
+// interrupted publishes the search statistics and checks
+// if the search was cancelled.
+func (s *solver) interrupted() bool {
+	s.publish()
+
+	if s.ctx == nil {
+		return false
+	}
+
+	if err := s.ctx.Err(); err != nil {
+		s.err = err
+		return true
+	}
+	return false
+}
+
+// search explores the states reachable from the current one and
+// calls visit for each solution found. It stops as soon as visit
+// returns true and reports whether it was stopped.
+func (s *solver) search(visit func() bool) bool {
+	s.nodes++
+	if s.nodes%checkInterval == 0 && s.interrupted() {
+		return true
+	}
+
+	p, moves := s.choose(make([]int, 0, 4))
+	if p < 0 {
+		if s.free == 0 {
+			return visit()
+		}
+		return false
+	}
+	s.branching++
+	s.branches += int64(len(moves))
+
+	for _, n := range moves {
+		s.extend(p, n)
+		if s.feasible() && s.search(visit) {
+			s.retract(p)
+			return true
+		}
+		s.retract(p)
+	}
+
+	return false
+}
+
+// paths converts the current (complete) state into board paths.
+func (s *solver) paths() []*Path {
+	paths := make([]*Path, len(s.pairs))
+	for i, p := range s.pairs {
+		start, end := p.start, p.end
+		path := &Path{StartDot: &start, EndDot: &end}
+		t := s.trails[i][0]
+		for j := 1; j < len(t); j++ {
+			path.AddLine(s.coord(t[j-1]), s.coord(t[j]))
+		}
+		paths[i] = path
+	}
+	return paths
+}
+
+// Solve finds a solution for a level: a path for each pair of dots
+// such that all the squares of the board are covered.
+// The paths are returned in the order the colors appear in the level.
+func Solve(level *Level) ([]*Path, error) {
+	return SolveContext(context.Background(), level, WithWorkers(1))
+}
+
+// Solutions stores the result of a solution count.
+type Solutions struct {
+	// The number of solutions found.
+	Count int
+
+	// True if the count reached the limit, so the level
+	// may have more solutions than the ones found.
+	Capped bool
+
+	// The solutions found (useful for debugging the levels
+	// having several solutions).
+	Paths [][]*Path
+}
+
+// Unique checks if the level has exactly one solution.
+func (s *Solutions) Unique() bool {
+	return s.Count == 1 && !s.Capped
+}
+
+// CountSolutions counts the solutions of a level, stopping
+// as soon as limit solutions were found.
+func CountSolutions(level *Level, limit int) (*Solutions, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("Invalid value for the solutions limit: %d", limit)
+	}
+
+	s, err := newSolver(level)
+	if err != nil {
+		return nil, err
+	}
+
+	sols := &Solutions{}
+	if s.feasible() {
+		sols.Capped = s.search(func() bool {
+			sols.Count++
+			sols.Paths = append(sols.Paths, s.paths())
+			return sols.Count >= limit
+		})
+	}
+
+	return sols, nil
+}
+
+// IsUnique checks if a level has exactly one solution.
+func IsUnique(level *Level) (bool, error) {
+	sols, err := CountSolutions(level, 2)
+	if err != nil {
+		return false, err
+	}
+	return sols.Count == 1, nil
+}
@@ -0,0 +1,284 @@
+package game
+
+import (
+	"bytes"
+	"connect-dots/graphics"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolve(t *testing.T) {
+	for _, file := range []string{"../data/5/0.json", "../data/5/1.json"} {
+		l, err := LoadFromFile(file)
+		assert.Nil(t, err)
+
+		paths, err := Solve(l)
+		assert.Nil(t, err, file)
+		assert.Equal(t, len(l.Dots)/2, len(paths))
+
+		b := NewBoard(l.Width, l.Height)
+		for _, p := range paths {
+			b.AddPath(p)
+			last := p.StartDot.Location
+			for _, line := range p.Lines {
+				assert.Equal(t, last, line.From)
+				last = line.To
+			}
+			assert.Equal(t, p.EndDot.Location, last)
+		}
+		assert.Equal(t, l.Width*l.Height, b.Coverage(), file)
+	}
+}
+
+func TestSolveNoSolution(t *testing.T) {
+	var json = []byte(`
+	{
+	"size": 2,
+	"dots": [
+		{"x": 0, "y": 0, "color": "red"},
+		{"x": 1, "y": 1, "color": "red"},
+		{"x": 1, "y": 0, "color": "blue"},
+		{"x": 0, "y": 1, "color": "blue"}
+	]
+	}
+`)
+
+	l, err := Load(json)
+	assert.Nil(t, err)
+
+	paths, err := Solve(l)
+	assert.Nil(t, paths)
+	assert.Equal(t, ErrNoSolution, err)
+}
+
+func TestSolveUnpairedDot(t *testing.T) {
+	l := &Level{Width: 5, Height: 5, Dots: []Dot{{Location: NewCoord(1, 2)}}}
+
+	paths, err := Solve(l)
+	assert.Nil(t, paths)
+	assert.NotNil(t, err)
+}
+
+func TestCountSolutions(t *testing.T) {
+	var json = []byte(`
+	{
+	"size": 3,
+	"dots": [
+		{"x": 0, "y": 0, "color": "red"},
+		{"x": 2, "y": 2, "color": "red"}
+	]
+	}
+`)
+
+	l, err := Load(json)
+	assert.Nil(t, err)
+
+	sols, err := CountSolutions(l, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, sols.Count)
+	assert.False(t, sols.Capped)
+	assert.False(t, sols.Unique())
+	assert.Len(t, sols.Paths, 2)
+
+	a := NewSolvedBoard(l.Width, l.Height, sols.Paths[0])
+	b := NewSolvedBoard(l.Width, l.Height, sols.Paths[1])
+	assert.Equal(t, l.Width*l.Height, a.Coverage())
+	assert.Equal(t, l.Width*l.Height, b.Coverage())
+
+	sols, err = CountSolutions(l, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, sols.Count)
+	assert.True(t, sols.Capped)
+
+	unique, err := IsUnique(l)
+	assert.Nil(t, err)
+	assert.False(t, unique)
+}
+
+func TestIsUnique(t *testing.T) {
+	l, err := LoadFromFile("../data/5/0.json")
+	assert.Nil(t, err)
+
+	unique, err := IsUnique(l)
+	assert.Nil(t, err)
+	assert.True(t, unique)
+}
+
+func TestSolveSAT(t *testing.T) {
+	for _, file := range []string{"../data/5/0.json", "../data/5/1.json"} {
+		l, err := LoadFromFile(file)
+		assert.Nil(t, err)
+
+		paths, err := SolveSAT(l)
+		assert.Nil(t, err, file)
+		assert.Equal(t, l.Width*l.Height, NewSolvedBoard(l.Width, l.Height, paths).Coverage(), file)
+	}
+}
+
+func TestSolveSATLargeBoard(t *testing.T) {
+	// each row connects a pair of dots placed on the left and right edges
+	l := &Level{Width: 10, Height: 10}
+	for y := int32(0); y < l.Height; y++ {
+		c := graphics.Color(y)
+		l.Dots = append(l.Dots,
+			Dot{Location: NewCoord(0, y), Color: c},
+			Dot{Location: NewCoord(l.Width-1, y), Color: c})
+	}
+
+	paths, err := SolveSAT(l)
+	assert.Nil(t, err)
+	assert.Equal(t, l.Width*l.Height, NewSolvedBoard(l.Width, l.Height, paths).Coverage())
+}
+
+func TestSolveSATNoSolution(t *testing.T) {
+	l := &Level{Width: 2, Height: 2, Dots: []Dot{
+		{Location: NewCoord(0, 0), Color: graphics.Red},
+		{Location: NewCoord(1, 1), Color: graphics.Red},
+		{Location: NewCoord(1, 0), Color: graphics.Blue},
+		{Location: NewCoord(0, 1), Color: graphics.Blue},
+	}}
+
+	paths, err := SolveSAT(l)
+	assert.Nil(t, paths)
+	assert.Equal(t, ErrNoSolution, err)
+}
+
+func TestWriteDIMACS(t *testing.T) {
+	l, err := LoadFromFile("../data/5/0.json")
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+	assert.Nil(t, WriteDIMACS(&buf, l))
+	assert.Contains(t, buf.String(), "p cnf ")
+}
+
+func TestSolveContext(t *testing.T) {
+	l, err := LoadFromFile("../data/5/1.json")
+	assert.Nil(t, err)
+
+	var last Progress
+	paths, err := SolveContext(context.Background(), l,
+		WithWorkers(4),
+		WithProgress(func(p Progress) { last = p }, time.Millisecond))
+	assert.Nil(t, err)
+	assert.Equal(t, l.Width*l.Height, NewSolvedBoard(l.Width, l.Height, paths).Coverage())
+	assert.True(t, last.Nodes > 0)
+}
+
+func TestSolveContextCancelled(t *testing.T) {
+	l, err := LoadFromFile("../data/5/1.json")
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	paths, err := SolveContext(ctx, l)
+	assert.Nil(t, paths)
+	assert.Equal(t, context.Canceled, err)
+
+	paths, err = SolveSATContext(ctx, l)
+	assert.Nil(t, paths)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestSplitCancelled(t *testing.T) {
+	l, err := LoadFromFile("../data/5/1.json")
+	assert.Nil(t, err)
+	s, err := newSolver(l)
+	assert.Nil(t, err)
+
+	assert.NotEmpty(t, s.split(context.Background(), 4))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Empty(t, s.split(ctx, 4))
+}
+
+func TestSolveFixedPaths(t *testing.T) {
+	l, err := LoadFromFile("../data/5/0.json")
+	assert.Nil(t, err)
+
+	sol, err := Solve(l)
+	assert.Nil(t, err)
+
+	// a prefix of a solution path drawn from its end dot
+	yellow := sol[1]
+	prefix := &Path{StartDot: yellow.EndDot}
+	last := yellow.Lines[len(yellow.Lines)-1]
+	prefix.AddLine(last.To, last.From)
+
+	paths, err := SolveContext(context.Background(), l, WithFixedPaths([]*Path{prefix}))
+	assert.Nil(t, err)
+	assert.Equal(t, *yellow.EndDot, *paths[1].StartDot)
+	assert.Equal(t, prefix.Lines[0].To, paths[1].Lines[0].To)
+
+	// a path which leaves the dot in the wrong direction
+	red := sol[0]
+	wrong := &Path{StartDot: red.StartDot}
+	for _, d := range []Coordinate{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+		c := NewCoord(red.StartDot.Location.X+d.X, red.StartDot.Location.Y+d.Y)
+		if c.IsValid() && c.X < l.Width && c.Y < l.Height && c != red.Lines[0].To {
+			wrong.AddLine(red.StartDot.Location, c)
+			break
+		}
+	}
+
+	paths, err = SolveContext(context.Background(), l, WithFixedPaths([]*Path{wrong}))
+	assert.Nil(t, paths)
+	assert.Equal(t, ErrNoSolution, err)
+
+	paths, offending, err := findHint(context.Background(), l, []*Path{prefix, wrong})
+	assert.Nil(t, paths)
+	assert.Nil(t, err)
+	assert.Equal(t, wrong, offending)
+}
+
+func TestSolveRectangular(t *testing.T) {
+	l, err := Load(rectangularJson)
+	assert.Nil(t, err)
+
+	paths, err := Solve(l)
+	assert.Nil(t, err)
+	assert.Empty(t, Verify(l, paths))
+	assert.Equal(t, int32(24), NewSolvedBoard(l.Width, l.Height, paths).Coverage())
+
+	unique, err := IsUnique(l)
+	assert.Nil(t, err)
+	assert.True(t, unique)
+
+	paths, err = SolveSAT(l)
+	assert.Nil(t, err)
+	assert.Empty(t, Verify(l, paths))
+}
+
+func TestSolveShape(t *testing.T) {
+	l, err := Load(ringJson)
+	assert.Nil(t, err)
+
+	paths, err := Solve(l)
+	assert.Nil(t, err)
+	assert.Empty(t, Verify(l, paths))
+
+	b := NewSolvedBoard(l.Width, l.Height, paths)
+	b.SetShape(l.Blocked, l.Walls)
+	assert.Equal(t, int32(12), b.Squares())
+	assert.Equal(t, b.Squares(), b.Coverage())
+
+	unique, err := IsUnique(l)
+	assert.Nil(t, err)
+	assert.True(t, unique)
+
+	paths, err = SolveSAT(l)
+	assert.Nil(t, err)
+	assert.Empty(t, Verify(l, paths))
+
+	// the wall cuts the only way of the red path
+	l.Walls = []Wall{{Coordinate{1, 0}, Coordinate{2, 0}}}
+	_, err = Solve(l)
+	assert.Equal(t, ErrNoSolution, err)
+	_, err = SolveSAT(l)
+	assert.Equal(t, ErrNoSolution, err)
+}
@@ -0,0 +1,93 @@
+package game
+
+import (
+	"connect-dots/graphics"
+	"fmt"
+)
+
+// withMetadata returns a level having the metadata of the level (the
+// difficulty, the name, the author, the par and the description), left
+// unchanged by the symmetries and the recolorings.
+func (l *Level) withMetadata() *Level {
+	return &Level{
+		Difficulty:  l.Difficulty,
+		Name:        l.Name,
+		Author:      l.Author,
+		Par:         l.Par,
+		Description: l.Description,
+	}
+}
+
+// transformed returns the image of the level by a symmetry of the board.
+func (l *Level) transformed(s symmetry) *Level {
+	t := l.withMetadata()
+	t.Dots = make([]Dot, len(l.Dots))
+	t.Width, t.Height = s.dimensions(l.Width, l.Height)
+	for i, dot := range l.Dots {
+		t.Dots[i] = Dot{Location: s(dot.Location, l.Width, l.Height), Color: dot.Color}
+	}
+	for _, c := range l.Blocked {
+		t.Blocked = append(t.Blocked, s(c, l.Width, l.Height))
+	}
+	for _, w := range l.Walls {
+		t.Walls = append(t.Walls, Wall{s(w.From, l.Width, l.Height), s(w.To, l.Width, l.Height)})
+	}
+	return t
+}
+
+// Rotate returns the level rotated clockwise by the given angle
+// (a multiple of 90 degrees).
+func (l *Level) Rotate(degrees int) (*Level, error) {
+	switch (degrees%360 + 360) % 360 {
+	case 0:
+		return l.transformed(identity), nil
+	case 90:
+		return l.transformed(rotate90), nil
+	case 180:
+		return l.transformed(rotate180), nil
+	case 270:
+		return l.transformed(rotate270), nil
+	}
+	return nil, fmt.Errorf("Invalid value for degrees: %d", degrees)
+}
+
+// Mirror returns the level reflected left to right.
+func (l *Level) Mirror() *Level {
+	return l.transformed(mirror)
+}
+
+// Transpose returns the level reflected along its main diagonal
+// (the rows become the columns).
+func (l *Level) Transpose() *Level {
+	return l.transformed(transpose)
+}
+
+// Recolor returns the level with its colors replaced as given by the map
+// (the colors missing from the map are kept). Two colors of the level
+// must not be given the same color.
+func (l *Level) Recolor(colors map[graphics.Color]graphics.Color) (*Level, error) {
+	t := l.withMetadata()
+	t.Width, t.Height = l.Width, l.Height
+	t.Dots = make([]Dot, len(l.Dots))
+	t.Blocked = append(t.Blocked, l.Blocked...)
+	t.Walls = append(t.Walls, l.Walls...)
+
+	from := make(map[graphics.Color]graphics.Color)
+	for i, dot := range l.Dots {
+		c, ok := colors[dot.Color]
+		if !ok {
+			c = dot.Color
+		}
+		if !c.IsValid() {
+			return nil, fmt.Errorf("Invalid value for color: %d", c)
+		}
+
+		if f, ok := from[c]; ok && f != dot.Color {
+			return nil, fmt.Errorf("The colors %s and %s are both recolored %s", f, dot.Color, c)
+		}
+		from[c] = dot.Color
+
+		t.Dots[i] = Dot{Location: dot.Location, Color: c}
+	}
+	return t, nil
+}
@@ -0,0 +1,141 @@
+package game
+
+import (
+	"connect-dots/graphics"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransform(t *testing.T) {
+	l := levelOf(t, 5, `[
+		{"x": 0, "y": 0, "color": "red"},
+		{"x": 4, "y": 1, "color": "red"}]`)
+
+	at := func(l *Level) []Coordinate {
+		return []Coordinate{l.Dots[0].Location, l.Dots[1].Location}
+	}
+
+	r, err := l.Rotate(90)
+	assert.Nil(t, err)
+	assert.Equal(t, []Coordinate{{4, 0}, {3, 4}}, at(r))
+
+	r, err = l.Rotate(180)
+	assert.Nil(t, err)
+	assert.Equal(t, []Coordinate{{4, 4}, {0, 3}}, at(r))
+
+	r, err = l.Rotate(-90)
+	assert.Nil(t, err)
+	assert.Equal(t, []Coordinate{{0, 4}, {1, 0}}, at(r))
+
+	_, err = l.Rotate(45)
+	assert.Error(t, err)
+
+	assert.Equal(t, []Coordinate{{4, 0}, {0, 1}}, at(l.Mirror()))
+	assert.Equal(t, []Coordinate{{0, 0}, {1, 4}}, at(l.Transpose()))
+
+	// the level itself is left as it is
+	assert.Equal(t, []Coordinate{{0, 0}, {4, 1}}, at(l))
+}
+
+func TestRecolor(t *testing.T) {
+	l := levelOf(t, 5, `[
+		{"x": 0, "y": 0, "color": "red"},
+		{"x": 4, "y": 0, "color": "red"},
+		{"x": 0, "y": 4, "color": "blue"},
+		{"x": 4, "y": 4, "color": "blue"}]`)
+
+	r, err := l.Recolor(map[graphics.Color]graphics.Color{graphics.Red: graphics.Blue, graphics.Blue: graphics.Red})
+	assert.Nil(t, err)
+	assert.Equal(t, graphics.Blue, r.Dots[0].Color)
+	assert.Equal(t, graphics.Red, r.Dots[2].Color)
+
+	_, err = l.Recolor(map[graphics.Color]graphics.Color{graphics.Red: graphics.Blue})
+	assert.Error(t, err)
+}
+
+func TestTransformSymmetric(t *testing.T) {
+	l, err := Generate(6, 5, 7, WithUniqueSolution())
+	assert.Nil(t, err)
+
+	for _, s := range symmetries {
+		image := l.transformed(s)
+
+		// the transformed level is valid Load input
+		data, err := image.Marshal()
+		assert.Nil(t, err)
+		loaded, err := Load(data)
+		assert.Nil(t, err)
+		assert.Equal(t, image, loaded)
+
+		paths, err := Solve(image)
+		assert.Nil(t, err)
+		assert.Empty(t, Verify(image, paths))
+
+		d, err := Rate(image)
+		assert.Nil(t, err)
+		assert.Equal(t, l.Difficulty, d)
+	}
+}
+
+func TestTransformRectangular(t *testing.T) {
+	l, err := Load(rectangularJson)
+	assert.Nil(t, err)
+
+	r, err := l.Rotate(90)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(6), r.Width)
+	assert.Equal(t, int32(4), r.Height)
+	assert.Equal(t, Coordinate{5, 0}, r.Dots[0].Location)
+
+	for _, s := range symmetries {
+		image := l.transformed(s)
+		paths, err := Solve(image)
+		assert.Nil(t, err)
+		assert.Empty(t, Verify(image, paths))
+		assert.Equal(t, CanonicalKey(l), CanonicalKey(image))
+	}
+}
+
+func TestTransformShape(t *testing.T) {
+	l, err := Load(ringJson)
+	assert.Nil(t, err)
+	l.Blocked = l.Blocked[:1]
+	l.Walls = []Wall{{Coordinate{0, 2}, Coordinate{0, 3}}}
+
+	r, err := l.Rotate(90)
+	assert.Nil(t, err)
+	assert.Equal(t, []Coordinate{{2, 1}}, r.Blocked)
+	assert.Equal(t, []Wall{{Coordinate{1, 0}, Coordinate{0, 0}}}, r.Walls)
+
+	for _, s := range symmetries {
+		assert.Equal(t, CanonicalKey(l), CanonicalKey(l.transformed(s)))
+	}
+
+	// another shape, the same dots
+	o, err := Load(ringJson)
+	assert.Nil(t, err)
+	assert.NotEqual(t, CanonicalKey(l), CanonicalKey(o))
+	assert.False(t, l.SameShape(o))
+}
+
+func TestTransformMetadata(t *testing.T) {
+	l, err := Load([]byte(`{"size": 5, "name": "Twins", "author": "Ann", "difficulty": 2, "par": 2,
+		"description": "Two long paths", "dots": [
+		{"x": 0, "y": 0, "color": "red"}, {"x": 4, "y": 0, "color": "red"},
+		{"x": 0, "y": 1, "color": "blue"}, {"x": 4, "y": 4, "color": "blue"}]}`))
+	assert.Nil(t, err)
+
+	r, err := l.Rotate(90)
+	assert.Nil(t, err)
+	c, err := l.Recolor(map[graphics.Color]graphics.Color{graphics.Red: graphics.Green})
+	assert.Nil(t, err)
+
+	for _, v := range []*Level{r, l.Mirror(), l.Transpose(), c} {
+		assert.Equal(t, "Twins", v.Name)
+		assert.Equal(t, "Ann", v.Author)
+		assert.Equal(t, Hard, v.Difficulty)
+		assert.Equal(t, int32(2), v.Par)
+		assert.Equal(t, "Two long paths", v.Description)
+	}
+}
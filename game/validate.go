@@ -0,0 +1,174 @@
+package game
+
+import (
+	"connect-dots/graphics"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DotErrorKind is the kind of a problem of a dot of a level file.
+type DotErrorKind int
+
+const (
+	// The dot is outside the board.
+	DotOutsideBoard DotErrorKind = iota
+	// The color of the dot has no known name.
+	UnknownColor
+	// Another dot is on the same square.
+	DuplicateDot
+	// The color of the dot appears only once.
+	SingleDot
+	// The color of the dot already appears twice.
+	ExtraDot
+	// The dot is on a blocked square.
+	BlockedDot
+)
+
+var dotErrorMessages = []string{
+	"is outside the board",
+	"has an unknown color",
+	"is on the same square as dot %d",
+	"is the only dot of its color",
+	"is the third dot of its color (the first two are dots %d and %d)",
+	"is on a blocked square",
+}
+
+// DotError reports an invalid dot of a level file.
+type DotError struct {
+	Kind DotErrorKind
+
+	// The index of the dot in the level file.
+	Index int
+
+	// The location and the color (as named in the file) of the dot.
+	Location Coordinate
+	Color    string
+
+	// The indexes of the other dots involved (for the duplicate
+	// dots and the extra dots).
+	Others []int
+}
+
+func (e *DotError) Error() string {
+	reason := fmt.Sprintf("error %d", int(e.Kind))
+	if e.Kind >= 0 && int(e.Kind) < len(dotErrorMessages) {
+		others := make([]interface{}, len(e.Others))
+		for i, o := range e.Others {
+			others[i] = o
+		}
+		reason = fmt.Sprintf(dotErrorMessages[e.Kind], others...)
+	}
+
+	return fmt.Sprintf("Dot %d at (%d,%d) of color %q %s",
+		e.Index, e.Location.X, e.Location.Y, e.Color, reason)
+}
+
+// DotErrors lists all the invalid dots of a level file.
+type DotErrors []*DotError
+
+func (es DotErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// validateDots checks the dots of a level file: they must be inside the
+// board, on distinct squares which are not blocked, with known colors each
+// appearing twice.
+func validateDots(width, height int32, blocked []Coordinate, dots []levelDot) error {
+	var errs DotErrors
+	dotError := func(k DotErrorKind, i int, others ...int) {
+		d := dots[i]
+		errs = append(errs, &DotError{
+			Kind:     k,
+			Index:    i,
+			Location: Coordinate{d.X, d.Y},
+			Color:    d.Color,
+			Others:   others,
+		})
+	}
+
+	isBlocked := make(map[Coordinate]bool)
+	for _, c := range blocked {
+		isBlocked[c] = true
+	}
+
+	seen := make(map[Coordinate]int)
+	colors := make(map[graphics.Color][]int)
+	var order []graphics.Color
+	for i, d := range dots {
+		c := Coordinate{d.X, d.Y}
+		if c.X < 0 || c.Y < 0 || c.X >= width || c.Y >= height {
+			dotError(DotOutsideBoard, i)
+		} else if isBlocked[c] {
+			dotError(BlockedDot, i)
+		} else if j, ok := seen[c]; ok {
+			dotError(DuplicateDot, i, j)
+		} else {
+			seen[c] = i
+		}
+
+		clr, ok := graphics.ParseColor(d.Color)
+		if !ok {
+			dotError(UnknownColor, i)
+			continue
+		}
+
+		is := colors[clr]
+		if len(is) >= 2 {
+			dotError(ExtraDot, i, is[0], is[1])
+		}
+		if len(is) == 0 {
+			order = append(order, clr)
+		}
+		colors[clr] = append(is, i)
+	}
+
+	for _, clr := range order {
+		if is := colors[clr]; len(is) == 1 {
+			dotError(SingleDot, is[0])
+		}
+	}
+
+	if len(errs) > 0 {
+		sort.SliceStable(errs, func(i, j int) bool { return errs[i].Index < errs[j].Index })
+		return errs
+	}
+	return nil
+}
+
+// validateShape checks the blocked squares and the walls of a level file:
+// the blocked squares must be distinct squares inside the board, and the
+// walls must separate orthogonal neighbours.
+func validateShape(width, height int32, blocked []Coordinate, walls []Wall) error {
+	inside := func(c Coordinate) bool {
+		return c.X >= 0 && c.Y >= 0 && c.X < width && c.Y < height
+	}
+
+	seen := make(map[Coordinate]bool)
+	for _, c := range blocked {
+		if !inside(c) {
+			return fmt.Errorf("Blocked square (%d,%d) is outside the board", c.X, c.Y)
+		}
+		if seen[c] {
+			return fmt.Errorf("Square (%d,%d) is blocked twice", c.X, c.Y)
+		}
+		seen[c] = true
+	}
+	if len(seen) == int(width*height) {
+		return errors.New("All the squares of the board are blocked")
+	}
+
+	for _, w := range walls {
+		dx, dy := w.To.X-w.From.X, w.To.Y-w.From.Y
+		if !inside(w.From) || !inside(w.To) || dx*dx+dy*dy != 1 {
+			return fmt.Errorf("Wall (%d,%d)-(%d,%d) does not separate adjacent squares",
+				w.From.X, w.From.Y, w.To.X, w.To.Y)
+		}
+	}
+	return nil
+}
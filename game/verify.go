@@ -0,0 +1,206 @@
+package game
+
+import (
+	"connect-dots/graphics"
+	"fmt"
+	"strings"
+)
+
+// ViolationKind is the kind of a rule broken by a solution.
+type ViolationKind int
+
+const (
+	// A pair of dots is not connected by a path.
+	Unconnected ViolationKind = iota
+	// A path does not start or end on the dots of its color.
+	WrongEndpoint
+	// A line does not start where the previous line of its path ends.
+	BrokenPath
+	// A line leaves the board.
+	OutsideBoard
+	// A line joins squares which are not orthogonal neighbours.
+	NotAdjacent
+	// A square is covered twice (by two paths or twice by the same path).
+	Overlap
+	// Some squares are not covered by any path.
+	Uncovered
+	// A line enters a blocked square.
+	OnBlockedSquare
+	// A line crosses a wall.
+	CrossedWall
+)
+
+var violationNames = []string{
+	"unconnected dots",
+	"wrong endpoint",
+	"broken path",
+	"outside the board",
+	"not adjacent",
+	"overlap",
+	"uncovered squares",
+	"blocked square",
+	"crossed wall",
+}
+
+// String returns the name of the violation kind.
+func (k ViolationKind) String() string {
+	if k >= 0 && int(k) < len(violationNames) {
+		return violationNames[k]
+	}
+	return fmt.Sprintf("violation %d", int(k))
+}
+
+// Violation is a rule broken by a solution.
+type Violation struct {
+	// The kind of the rule broken.
+	Kind ViolationKind
+
+	// The color of the path (or of the dots) involved
+	// (NoColor for the uncovered squares).
+	Color graphics.Color
+
+	// The squares involved.
+	Coordinates []Coordinate
+}
+
+// String describes the violation.
+func (v Violation) String() string {
+	cs := make([]string, len(v.Coordinates))
+	for i, c := range v.Coordinates {
+		cs[i] = fmt.Sprintf("(%d,%d)", c.X, c.Y)
+	}
+
+	if v.Color == graphics.NoColor {
+		return fmt.Sprintf("%s: %s", v.Kind, strings.Join(cs, " "))
+	}
+	return fmt.Sprintf("%s (%s): %s", v.Kind, v.Color, strings.Join(cs, " "))
+}
+
+// Verify checks a solution of a level: every pair of dots must be connected
+// by a path made of lines between orthogonal neighbours, the paths must not
+// overlap and they must cover the whole board (the blocked squares left
+// out, and no line crossing a wall). It returns the rules broken (none for
+// a valid solution).
+//
+// The paths having no lines are ignored, so the paths of a Board may be
+// verified as they are.
+func Verify(level *Level, paths []*Path) []Violation {
+	var vs []Violation
+	violation := func(k ViolationKind, clr graphics.Color, cs ...Coordinate) {
+		vs = append(vs, Violation{Kind: k, Color: clr, Coordinates: cs})
+	}
+
+	inside := func(c Coordinate) bool {
+		return c.X >= 0 && c.Y >= 0 && c.X < level.Width && c.Y < level.Height
+	}
+
+	walls := make(map[Wall]bool)
+	for _, w := range level.Walls {
+		walls[w] = true
+		walls[Wall{w.To, w.From}] = true
+	}
+
+	dots := make(map[Coordinate]graphics.Color)
+	covered := make(map[Coordinate]bool)
+	for _, dot := range level.Dots {
+		dots[dot.Location] = dot.Color
+		covered[dot.Location] = true
+	}
+
+	connected := make(map[graphics.Color]bool)
+	for _, path := range paths {
+		if path == nil || path.StartDot == nil || len(path.Lines) == 0 {
+			continue
+		}
+
+		clr := path.StartDot.Color
+		start := path.StartDot.Location
+		if c, ok := dots[start]; !ok || c != clr {
+			violation(WrongEndpoint, clr, start)
+		}
+
+		valid := true
+		crt := start
+		for i, l := range path.Lines {
+			if l.From != crt {
+				violation(BrokenPath, clr, crt, l.From)
+				valid = false
+			}
+			crt = l.To
+
+			if !inside(l.From) || !inside(l.To) {
+				violation(OutsideBoard, clr, l.From, l.To)
+				valid = false
+				continue
+			}
+
+			dx, dy := l.To.X-l.From.X, l.To.Y-l.From.Y
+			if dx*dx+dy*dy != 1 {
+				violation(NotAdjacent, clr, l.From, l.To)
+				valid = false
+			}
+
+			if level.IsBlocked(l.From) || level.IsBlocked(l.To) {
+				violation(OnBlockedSquare, clr, l.From, l.To)
+				valid = false
+			}
+
+			if walls[Wall{l.From, l.To}] {
+				violation(CrossedWall, clr, l.From, l.To)
+				valid = false
+			}
+
+			// the last line ends on the other dot of the path
+			if c, ok := dots[l.To]; ok && c == clr && l.To != start && i == len(path.Lines)-1 {
+				continue
+			}
+
+			if covered[l.To] {
+				violation(Overlap, clr, l.To)
+				valid = false
+			}
+			covered[l.To] = true
+		}
+
+		end, ok := dots[crt]
+		if !ok || end != clr || crt == start ||
+			(path.EndDot != nil && path.EndDot.Location != crt) {
+			violation(WrongEndpoint, clr, crt)
+			valid = false
+		}
+
+		if valid {
+			connected[clr] = true
+		}
+	}
+
+	reported := make(map[graphics.Color]bool)
+	for _, dot := range level.Dots {
+		if connected[dot.Color] || reported[dot.Color] {
+			continue
+		}
+		reported[dot.Color] = true
+
+		var cs []Coordinate
+		for _, d := range level.Dots {
+			if d.Color == dot.Color {
+				cs = append(cs, d.Location)
+			}
+		}
+		violation(Unconnected, dot.Color, cs...)
+	}
+
+	var uncovered []Coordinate
+	for x := int32(0); x < level.Width; x++ {
+		for y := int32(0); y < level.Height; y++ {
+			if !covered[Coordinate{x, y}] && !level.IsBlocked(Coordinate{x, y}) {
+				uncovered = append(uncovered, Coordinate{x, y})
+			}
+		}
+	}
+	if len(uncovered) > 0 {
+		violation(Uncovered, graphics.NoColor, uncovered...)
+	}
+
+	return vs
+}
@@ -0,0 +1,107 @@
+package game
+
+import (
+	"connect-dots/graphics"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	l, err := LoadFromFile("../data/5/0.json")
+	assert.Nil(t, err)
+
+	paths, err := Solve(l)
+	assert.Nil(t, err)
+	assert.Empty(t, Verify(l, paths))
+
+	// the paths of a board (each complete path is stored twice)
+	b := NewSolvedBoard(l.Width, l.Height, paths)
+	var all []*Path
+	for _, p := range b.Paths {
+		all = append(all, p)
+	}
+	assert.Empty(t, Verify(l, all))
+
+	// a missing path
+	vs := Verify(l, paths[1:])
+	if assert.Len(t, vs, 2) {
+		assert.Equal(t, Unconnected, vs[0].Kind)
+		assert.Equal(t, paths[0].StartDot.Color, vs[0].Color)
+		assert.Equal(t, []Coordinate{paths[0].StartDot.Location, paths[0].EndDot.Location}, vs[0].Coordinates)
+		assert.Equal(t, Uncovered, vs[1].Kind)
+		assert.Equal(t, graphics.NoColor, vs[1].Color)
+		assert.Len(t, vs[1].Coordinates, len(paths[0].Lines)-1)
+	}
+}
+
+func TestVerifyViolations(t *testing.T) {
+	l := &Level{Width: 2, Height: 2, Dots: []Dot{
+		{Coordinate{0, 0}, graphics.Red},
+		{Coordinate{1, 0}, graphics.Red},
+		{Coordinate{0, 1}, graphics.Blue},
+		{Coordinate{1, 1}, graphics.Blue},
+	}}
+
+	path := func(clr graphics.Color, squares ...Coordinate) *Path {
+		p := &Path{StartDot: &Dot{squares[0], clr}}
+		for i := 1; i < len(squares); i++ {
+			p.AddLine(squares[i-1], squares[i])
+		}
+		return p
+	}
+
+	vs := Verify(l, []*Path{
+		path(graphics.Red, Coordinate{0, 0}, Coordinate{1, 0}),
+		path(graphics.Blue, Coordinate{0, 1}, Coordinate{1, 1}),
+	})
+	assert.Empty(t, vs)
+
+	// the red path goes through a blue dot, diagonally
+	vs = Verify(l, []*Path{
+		path(graphics.Red, Coordinate{0, 0}, Coordinate{1, 1}, Coordinate{1, 0}),
+		path(graphics.Blue, Coordinate{0, 1}, Coordinate{1, 1}),
+	})
+	assert.Equal(t, []Violation{
+		{NotAdjacent, graphics.Red, []Coordinate{{0, 0}, {1, 1}}},
+		{Overlap, graphics.Red, []Coordinate{{1, 1}}},
+		{Unconnected, graphics.Red, []Coordinate{{0, 0}, {1, 0}}},
+	}, vs)
+
+	// the blue path starts from a red dot and leaves the board
+	vs = Verify(l, []*Path{
+		path(graphics.Red, Coordinate{0, 0}, Coordinate{1, 0}),
+		path(graphics.Blue, Coordinate{0, 0}, Coordinate{0, -1}),
+	})
+	assert.Equal(t, []Violation{
+		{WrongEndpoint, graphics.Blue, []Coordinate{{0, 0}}},
+		{OutsideBoard, graphics.Blue, []Coordinate{{0, 0}, {0, -1}}},
+		{WrongEndpoint, graphics.Blue, []Coordinate{{0, -1}}},
+		{Unconnected, graphics.Blue, []Coordinate{{0, 1}, {1, 1}}},
+	}, vs)
+}
+
+func TestVerifyShape(t *testing.T) {
+	l, err := Load(ringJson)
+	assert.Nil(t, err)
+	l.Walls = []Wall{{Coordinate{3, 1}, Coordinate{3, 2}}}
+
+	red := &Path{StartDot: &l.Dots[0], EndDot: &l.Dots[1]}
+	red.AddLine(Coordinate{0, 0}, Coordinate{1, 0})
+	red.AddLine(Coordinate{1, 0}, Coordinate{1, 1})
+	blue := &Path{StartDot: &l.Dots[3], EndDot: &l.Dots[2]}
+	blue.AddLine(Coordinate{3, 1}, Coordinate{3, 2})
+
+	kinds := make(map[ViolationKind]bool)
+	for _, v := range Verify(l, []*Path{red, blue}) {
+		kinds[v.Kind] = true
+		if v.Kind == Uncovered {
+			for _, c := range v.Coordinates {
+				assert.False(t, l.IsBlocked(c))
+			}
+		}
+	}
+	assert.True(t, kinds[OnBlockedSquare])
+	assert.True(t, kinds[CrossedWall])
+	assert.True(t, kinds[Uncovered])
+}
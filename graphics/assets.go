@@ -11,17 +11,28 @@ import (
 // - the board (grid)
 // - the dots for each color
 // - the vertical and horizontal lines for each color
+// - the vertical and horizontal lines for each color revealed by the hints
 // The assests may be loaded from files where generated programatically.
+// The assets of a color are keyed by the color (missing for the colors
+// not prepared, see Prepare).
 type AssetsStorage struct {
-	Grid       Renderable
-	Dots       []Renderable
-	VertLines  []Renderable
-	HorizLines []Renderable
+	Grid           Renderable
+	Dots           map[Color]Renderable
+	VertLines      map[Color]Renderable
+	HorizLines     map[Color]Renderable
+	HintVertLines  map[Color]Renderable
+	HintHorizLines map[Color]Renderable
 }
 
 // NewAssetsStorage creates a new graphics assests storage.
 func NewAssetsStorage() *AssetsStorage {
-	return &AssetsStorage{}
+	return &AssetsStorage{
+		Dots:           make(map[Color]Renderable),
+		VertLines:      make(map[Color]Renderable),
+		HorizLines:     make(map[Color]Renderable),
+		HintVertLines:  make(map[Color]Renderable),
+		HintHorizLines: make(map[Color]Renderable),
+	}
 }
 
 // Init creates the graphics assests.
@@ -29,40 +40,55 @@ func (s *AssetsStorage) Init(renderer *Renderer, config *config.Config) error {
 	grid := CreateGrid(renderer, config)
 	s.Grid = grid
 
-	s.Dots = make([]Renderable, len(Colors))
-	s.VertLines = make([]Renderable, len(Colors))
-	s.HorizLines = make([]Renderable, len(Colors))
-	for i, c := range Colors {
-		s.Dots[i] = createDot(c, renderer, config)
-		s.VertLines[i] = createVLine(c, renderer, config)
-		s.HorizLines[i] = createHLine(c, renderer, config)
+	colors := make([]Color, len(Colors))
+	for i := range Colors {
+		colors[i] = Color(i)
 	}
+	s.Prepare(renderer, config, colors)
 
 	return nil
 }
 
+// Prepare creates the graphics assets of the given colors
+// (the colors already prepared are skipped).
+func (s *AssetsStorage) Prepare(renderer *Renderer, config *config.Config, colors []Color) {
+	for _, c := range colors {
+		if !c.IsValid() {
+			continue
+		}
+		if _, ok := s.Dots[c]; ok {
+			continue
+		}
+
+		rgb := c.RGBA()
+		s.Dots[c] = createDot(rgb, renderer, config)
+		s.VertLines[c] = createVLine(rgb, renderer, config, false)
+		s.HorizLines[c] = createHLine(rgb, renderer, config, false)
+		s.HintVertLines[c] = createVLine(rgb, renderer, config, true)
+		s.HintHorizLines[c] = createHLine(rgb, renderer, config, true)
+	}
+}
+
 // Destroy releases all the graphics assests.
 func (s *AssetsStorage) Destroy() {
 	s.Grid.Destroy()
-	for _, dot := range s.Dots {
-		dot.Destroy()
-	}
-	for _, vl := range s.VertLines {
-		vl.Destroy()
-	}
-	for _, hl := range s.HorizLines {
-		hl.Destroy()
+	for _, assets := range []map[Color]Renderable{s.Dots, s.VertLines, s.HorizLines, s.HintVertLines, s.HintHorizLines} {
+		for _, a := range assets {
+			a.Destroy()
+		}
 	}
 }
 
 // CreateGrid creates a graphics object which is used to render
-// a grid of a given size.
+// a grid of the width, height and shape given by the configuration:
+// the blocked squares are left out and the walls are drawn between
+// the squares they separate.
 func CreateGrid(renderer *Renderer, cfg *config.Config) *Grid {
 	crtTarget := renderer.GetRenderTarget()
 	defer renderer.SetRenderTarget(crtTarget)
 
-	w := cfg.Size * cfg.SquareSize
-	h := w
+	w := cfg.Width * cfg.SquareSize
+	h := cfg.Height * cfg.SquareSize
 	r := &sdl.Rect{
 		X: (cfg.WindowWidth - w) / 2,
 		Y: (cfg.WindowHeight - h) / 2,
@@ -93,9 +119,19 @@ func CreateGrid(renderer *Renderer, cfg *config.Config) *Grid {
 		h,
 	)
 
+	blocked := make(map[sdl.Point]bool)
+	for _, p := range cfg.Blocked {
+		blocked[p] = true
+	}
+
 	renderer.SetRenderTarget(gt)
-	for x := 0; x < int(cfg.Size); x++ {
-		for y := 0; y < int(cfg.Size); y++ {
+	renderer.SetDrawColor(0, 0, 0, 0)
+	renderer.Clear()
+	for x := 0; x < int(cfg.Width); x++ {
+		for y := 0; y < int(cfg.Height); y++ {
+			if blocked[sdl.Point{X: int32(x), Y: int32(y)}] {
+				continue
+			}
 			renderer.Copy(t,
 				nil,
 				&sdl.Rect{
@@ -108,6 +144,19 @@ func CreateGrid(renderer *Renderer, cfg *config.Config) *Grid {
 		}
 	}
 
+	renderer.SetDrawColor(0, 0, 0, 255)
+	for _, w := range cfg.Walls {
+		a, b := w[0], w[1]
+		if a.X > b.X || a.Y > b.Y {
+			a, b = b, a
+		}
+		if a.Y == b.Y {
+			renderer.DrawVLine(b.X*cfg.SquareSize, a.Y*cfg.SquareSize, (a.Y+1)*cfg.SquareSize, 6)
+		} else {
+			renderer.DrawHLine(a.X*cfg.SquareSize, (a.X+1)*cfg.SquareSize, b.Y*cfg.SquareSize, 6)
+		}
+	}
+
 	return NewGrid(r, gt)
 }
 
@@ -140,8 +189,10 @@ func createDot(color sdl.Color,
 	return NewDot(r, t)
 }
 
+// createVLine creates a vertical line; the hinted lines are more opaque,
+// thicker and have a white core.
 func createVLine(color sdl.Color,
-	renderer *Renderer, cfg *config.Config) *Line {
+	renderer *Renderer, cfg *config.Config, hinted bool) *Line {
 
 	crtTarget := renderer.GetRenderTarget()
 	defer renderer.SetRenderTarget(crtTarget)
@@ -160,19 +211,26 @@ func createVLine(color sdl.Color,
 		r.H,
 	)
 	t.SetBlendMode(sdl.BLENDMODE_BLEND) //nolint
-	t.SetAlphaMod(50)                   //nolint
+	t.SetAlphaMod(lineAlpha(hinted))    //nolint
 	renderer.SetRenderTarget(t)
 
 	renderer.SetDrawColor(cfg.Color.R, cfg.Color.G, cfg.Color.B, cfg.Color.A)
 	renderer.Clear()
 	renderer.SetDrawColor(color.R, color.G, color.B, color.A)
-	renderer.DrawVLine(r.W/2, 0, r.H, 4)
+	if hinted {
+		renderer.DrawVLine(r.W/2, 0, r.H, 10)
+		renderer.SetDrawColor(255, 255, 255, 255)
+		renderer.DrawVLine(r.W/2, 0, r.H, 2)
+	} else {
+		renderer.DrawVLine(r.W/2, 0, r.H, 4)
+	}
 
 	return NewLine(r, t)
 }
 
+// createHLine creates a horizontal line (see createVLine).
 func createHLine(color sdl.Color,
-	renderer *Renderer, cfg *config.Config) *Line {
+	renderer *Renderer, cfg *config.Config, hinted bool) *Line {
 
 	crtTarget := renderer.GetRenderTarget()
 	defer renderer.SetRenderTarget(crtTarget)
@@ -191,13 +249,26 @@ func createHLine(color sdl.Color,
 		r.H,
 	)
 	t.SetBlendMode(sdl.BLENDMODE_BLEND) //nolint
-	t.SetAlphaMod(50)                   //nolint
+	t.SetAlphaMod(lineAlpha(hinted))    //nolint
 	renderer.SetRenderTarget(t)
 
 	renderer.SetDrawColor(cfg.Color.R, cfg.Color.G, cfg.Color.B, cfg.Color.A)
 	renderer.Clear()
 	renderer.SetDrawColor(color.R, color.G, color.B, color.A)
-	renderer.DrawHLine(0, r.W, r.H/2, 4)
+	if hinted {
+		renderer.DrawHLine(0, r.W, r.H/2, 10)
+		renderer.SetDrawColor(255, 255, 255, 255)
+		renderer.DrawHLine(0, r.W, r.H/2, 2)
+	} else {
+		renderer.DrawHLine(0, r.W, r.H/2, 4)
+	}
 
 	return NewLine(r, t)
 }
+
+func lineAlpha(hinted bool) uint8 {
+	if hinted {
+		return 120
+	}
+	return 50
+}
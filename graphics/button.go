@@ -0,0 +1,42 @@
+package graphics
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
+)
+
+// Button is a text framed by a rectangle which may be clicked.
+type Button struct {
+	text *Text
+
+	// The bounds of the button (known once it was drawn).
+	bounds sdl.Rect
+}
+
+// NewButton creates a Button graphics object.
+func NewButton(text string, f *ttf.Font) *Button {
+	return &Button{text: NewText(text, f)}
+}
+
+// Draw renders the button on a rendering target at the given position.
+func (b *Button) Draw(r *Renderer, pos sdl.Point) error {
+	const padding = 4
+
+	rc, err := b.text.draw(r, sdl.Point{X: pos.X + padding, Y: pos.Y + padding})
+	if err != nil {
+		return err
+	}
+
+	b.bounds = sdl.Rect{X: pos.X, Y: pos.Y, W: rc.W + 2*padding, H: rc.H + 2*padding}
+	r.SetDrawColor(255, 255, 0, 255)
+	r.DrawRect(&b.bounds)
+
+	return nil
+}
+
+// ClickedInside checks if the given screen coordinate is within
+// the bounds of the button.
+func (b *Button) ClickedInside(x, y int32) bool {
+	p := &sdl.Point{X: x, Y: y}
+	return p.InRect(&b.bounds)
+}
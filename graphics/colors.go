@@ -1,6 +1,13 @@
 package graphics
 
-import "github.com/veandco/go-sdl2/sdl"
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
 
 type Color int
 
@@ -32,3 +39,190 @@ var Colors = []sdl.Color{
 	sdl.Color{R: 255, G: 255, B: 255, A: 255},
 	sdl.Color{R: 0, G: 0, B: 0, A: 255},
 }
+
+var colorNames = []string{
+	"red",
+	"green",
+	"blue",
+	"yellow",
+	"magenta",
+	"cyan",
+	"pink",
+	"orange",
+	"brown",
+	"white",
+	"black",
+}
+
+// Palettes are the named palettes the level files may take colors from
+// ("tableau20:3" is the fourth color of the tableau20 palette).
+var Palettes = map[string][]sdl.Color{
+	"tableau20": {
+		{R: 31, G: 119, B: 180, A: 255},
+		{R: 174, G: 199, B: 232, A: 255},
+		{R: 255, G: 127, B: 14, A: 255},
+		{R: 255, G: 187, B: 120, A: 255},
+		{R: 44, G: 160, B: 44, A: 255},
+		{R: 152, G: 223, B: 138, A: 255},
+		{R: 214, G: 39, B: 40, A: 255},
+		{R: 255, G: 152, B: 150, A: 255},
+		{R: 148, G: 103, B: 189, A: 255},
+		{R: 197, G: 176, B: 213, A: 255},
+		{R: 140, G: 86, B: 75, A: 255},
+		{R: 196, G: 156, B: 148, A: 255},
+		{R: 227, G: 119, B: 194, A: 255},
+		{R: 247, G: 182, B: 210, A: 255},
+		{R: 127, G: 127, B: 127, A: 255},
+		{R: 199, G: 199, B: 199, A: 255},
+		{R: 188, G: 189, B: 34, A: 255},
+		{R: 219, G: 219, B: 141, A: 255},
+		{R: 23, G: 190, B: 207, A: 255},
+		{R: 158, G: 218, B: 229, A: 255},
+	},
+	"pastel": {
+		{R: 251, G: 180, B: 174, A: 255},
+		{R: 179, G: 205, B: 227, A: 255},
+		{R: 204, G: 235, B: 197, A: 255},
+		{R: 222, G: 203, B: 228, A: 255},
+		{R: 254, G: 217, B: 166, A: 255},
+		{R: 255, G: 255, B: 204, A: 255},
+		{R: 229, G: 216, B: 189, A: 255},
+		{R: 253, G: 218, B: 236, A: 255},
+	},
+}
+
+// The colors of the named palettes and the colors given by value
+// are numbered after the built-in colors: their number encodes the
+// palette and the index in the palette (paletteBase + 256*p + i where p
+// is the rank of the palette name), or the components of the color
+// (valueBase + 0xrrggbb). So the same name always gives the same color.
+const (
+	paletteBase Color = 1 << 16
+	valueBase   Color = 1 << 24
+)
+
+// paletteNames returns the names of the palettes in alphabetical order.
+func paletteNames() []string {
+	names := make([]string, 0, len(Palettes))
+	for name := range Palettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// palette returns the name of the palette of a palette color,
+// and the index of the color in the palette.
+func (c Color) palette() (string, int, bool) {
+	if c < paletteBase || c >= valueBase {
+		return "", 0, false
+	}
+
+	names := paletteNames()
+	p, i := int(c-paletteBase)/256, int(c-paletteBase)%256
+	if p >= len(names) || i >= len(Palettes[names[p]]) {
+		return "", 0, false
+	}
+	return names[p], i, true
+}
+
+// PaletteColors returns the colors of a named palette
+// (none for an unknown palette).
+func PaletteColors(name string) []Color {
+	for p, n := range paletteNames() {
+		if n != name {
+			continue
+		}
+
+		colors := make([]Color, len(Palettes[name]))
+		for i := range colors {
+			colors[i] = paletteBase + Color(256*p+i)
+		}
+		return colors
+	}
+	return nil
+}
+
+// String returns the name of the color.
+func (c Color) String() string {
+	if c >= 0 && int(c) < len(colorNames) {
+		return colorNames[c]
+	}
+	if c == NoColor {
+		return "none"
+	}
+
+	if name, i, ok := c.palette(); ok {
+		return fmt.Sprintf("%s:%d", name, i)
+	}
+	if c.IsValid() {
+		return fmt.Sprintf("#%06x", int(c-valueBase))
+	}
+	return fmt.Sprintf("color %d", int(c))
+}
+
+// IsValid checks if the color is a built-in color, a color of a named
+// palette or a color given by value.
+func (c Color) IsValid() bool {
+	if c >= 0 && int(c) < len(Colors) {
+		return true
+	}
+	if _, _, ok := c.palette(); ok {
+		return true
+	}
+	return c >= valueBase && c < valueBase+1<<24
+}
+
+// RGBA returns the components of the color.
+func (c Color) RGBA() sdl.Color {
+	if c >= 0 && int(c) < len(Colors) {
+		return Colors[c]
+	}
+
+	if name, i, ok := c.palette(); ok {
+		return Palettes[name][i]
+	}
+	if c.IsValid() {
+		v := int(c - valueBase)
+		return sdl.Color{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}
+	}
+	return sdl.Color{}
+}
+
+// ParseColor returns the color having the given name (see String): the
+// name of a built-in color, a hex value ("#ff8800") or a color of a named
+// palette ("tableau20:3"). The built-in color is returned for the value
+// of a built-in color.
+func ParseColor(name string) (Color, bool) {
+	for i, n := range colorNames {
+		if n == name {
+			return Color(i), true
+		}
+	}
+
+	if strings.HasPrefix(name, "#") {
+		v, err := strconv.ParseUint(name[1:], 16, 32)
+		if len(name) != 7 || err != nil {
+			return NoColor, false
+		}
+
+		rgb := sdl.Color{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}
+		for i, c := range Colors {
+			if c == rgb {
+				return Color(i), true
+			}
+		}
+		return valueBase + Color(v), true
+	}
+
+	if s := strings.Split(name, ":"); len(s) == 2 {
+		i, err := strconv.Atoi(s[1])
+		colors := PaletteColors(s[0])
+		if err != nil || i < 0 || i >= len(colors) {
+			return NoColor, false
+		}
+		return colors[i], true
+	}
+
+	return NoColor, false
+}
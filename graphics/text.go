@@ -1,6 +1,8 @@
 package graphics
 
 import (
+	"strings"
+
 	"github.com/veandco/go-sdl2/sdl"
 	"github.com/veandco/go-sdl2/ttf"
 )
@@ -20,22 +22,49 @@ func NewText(text string, f *ttf.Font) *Text {
 
 // Draw renders the text on a rendering target at the given position.
 func (t *Text) Draw(r *Renderer, pos sdl.Point) error {
+	_, err := t.draw(r, pos)
+	return err
+}
+
+// draw renders the text and returns the bounds of the rendered text.
+func (t *Text) draw(r *Renderer, pos sdl.Point) (sdl.Rect, error) {
 	s, err := t.font.RenderUTF8Solid(t.Text, sdl.Color{R: 255, G: 255, B: 0})
 	if err != nil {
-		return err
+		return sdl.Rect{}, err
 	}
 	defer s.Free()
 
 	tex, err := r.Renderer.CreateTextureFromSurface(s)
 	if err != nil {
-		return err
+		return sdl.Rect{}, err
 	}
 	defer tex.Destroy() //nolint
 
 	rc := sdl.Rect{X: pos.X, Y: pos.Y, W: s.W, H: s.H}
 	if err := r.Renderer.Copy(tex, nil, &rc); err != nil {
-		return err
+		return sdl.Rect{}, err
 	}
 
-	return nil
+	return rc, nil
+}
+
+// Wrap splits a text into lines of at most width characters (unless
+// a single word is longer), breaking the lines between the words.
+func Wrap(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, w := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(w) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += w
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
 }
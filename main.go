@@ -8,6 +8,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -17,10 +18,18 @@ import (
 
 func main() {
 	var (
-		size int
+		size   int
+		assist bool
+		seed   int64
+		daily  bool
+		replay string
 	)
 
 	flag.IntVar(&size, "size", 5, "the board size")
+	flag.BoolVar(&daily, "daily", false, "play the daily puzzle first")
+	flag.BoolVar(&assist, "assist", false, "draw the forced moves automatically")
+	flag.StringVar(&replay, "replay", "", "play first a generated level given as size:colors:seed (as logged)")
+	flag.Int64Var(&seed, "seed", 0, "the seed of the levels generated once the level files run out (random if 0)")
 	flag.Parse()
 
 	log, err := zap.NewDevelopment()
@@ -35,10 +44,18 @@ func main() {
 	}
 	defer sdl.Quit()
 
-	withSize := func(sz int32) config.Option {
-		return func(c *config.Config) { c.Size = sz }
+	dir, err := os.Getwd()
+	if err != nil {
+		log.Fatal("Failed to get the current working directory", zap.Error(err))
+	}
+
+	fileName := "0.json"
+	path := fmt.Sprintf("%s/data/%d/%s", dir, size, fileName)
+	l, err := game.LoadFromFile(path)
+	if err != nil {
+		log.Fatal("Failed to load the level", zap.Error(err))
 	}
-	config := config.New(withSize(int32(size)))
+	config := config.New(game.BoardConfig(l))
 
 	window, err := sdl.CreateWindow("dots connected",
 		sdl.WINDOWPOS_CENTERED, sdl.WINDOWPOS_CENTERED,
@@ -61,6 +78,12 @@ func main() {
 	}
 	defer font.Close()
 
+	small, err := ttf.OpenFont("data/fonts/test.ttf", 16)
+	if err != nil {
+		log.Fatal("Failed to open font", zap.Error(err))
+	}
+	defer small.Close()
+
 	renderer, err := sdl.CreateRenderer(window, -1, sdl.RENDERER_ACCELERATED|sdl.RENDERER_PRESENTVSYNC)
 	if err != nil {
 		log.Fatal("Failed to create the SDL renderer", zap.Error(err))
@@ -78,28 +101,43 @@ func main() {
 		log.Fatal("Failed to load the graphics assets", zap.Error(err))
 	}
 	defer storage.Destroy()
+	storage.Prepare(gr, config, l.Colors())
 
-	dir, err := os.Getwd()
+	streakFile, err := game.DefaultStreakFile()
 	if err != nil {
-		log.Fatal("Failed to get the current working directory", zap.Error(err))
+		log.Warn("The daily puzzle streak will not be saved", zap.Error(err))
 	}
 
-	fileName := "0.json"
-	path := fmt.Sprintf("%s/data/%d/%s", dir, size, fileName)
-	l, err := game.LoadFromFile(path)
-	if err != nil {
-		log.Fatal("Failed to load the level", zap.Error(err))
+	if seed == 0 {
+		seed = time.Now().UnixNano()
 	}
 
 	game := game.New(config, storage,
 		game.WithWindow(window),
 		game.WithMoveText(graphics.NewText("Moves: 0", font)),
 		game.WithCoverageText(graphics.NewText("Coverage: 0%", font)),
+		game.WithSolverText(graphics.NewText("", font)),
+		game.WithHintButton(graphics.NewButton("Hint", font)),
+		game.WithLevelText(graphics.NewText("", small)),
+		game.WithExplanationText(graphics.NewText("", small)),
+		game.WithAssist(assist),
 		game.WithLogger(log),
 		game.WithLevel(l),
 		game.WithFile(fileName),
+		game.WithSeed(seed),
+		game.WithStreakFile(streakFile),
 	)
 
+	if replay != "" {
+		if err := game.Replay(gr, replay); err != nil {
+			log.Fatal("Failed to replay the level", zap.Error(err))
+		}
+	}
+
+	if daily {
+		game.PlayDaily(gr, time.Now())
+	}
+
 	running := true
 	for running {
 		gr.SetDrawColor(0, 0, 0, 0)
@@ -123,15 +161,25 @@ func main() {
 
 			case *sdl.MouseMotionEvent:
 				game.MouseMove(t)
+
+			case *sdl.KeyboardEvent:
+				if t.Type == sdl.KEYDOWN {
+					if t.Keysym.Sym == sdl.K_d {
+						game.PlayDaily(gr, time.Now())
+						continue
+					}
+					game.KeyDown(t)
+				}
 			}
 		}
 
+		game.Update()
 		game.Draw(gr)
 		gr.Present()
 		sdl.Delay(5)
 
 		if game.Completed {
-			action, err := ui.LevelCompletedBox(game.Moves, window)
+			action, err := ui.LevelCompletedBox(game.LevelName(), game.Par(), game.Moves, game.Hints, game.Streak, window)
 			if err != nil {
 				log.Fatal("Internal error", zap.Error(err))
 			}
@@ -141,10 +189,14 @@ func main() {
 				game.Continue(gr)
 			case ui.Repeat:
 				game.Repeat()
+			case ui.Daily:
+				game.PlayDaily(gr, time.Now())
 			case ui.Quit:
+				game.Close()
 				os.Exit(0)
 			}
 		}
 	}
+	game.Close()
 	os.Exit(0)
 }
@@ -0,0 +1,37 @@
+package sat
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// WriteDIMACS writes the problem clauses in the DIMACS CNF format
+// so they can be checked with other SAT solvers.
+// The comments (if any) are written in the header.
+func (s *Solver) WriteDIMACS(w io.Writer, comments ...string) error {
+	bw := bufio.NewWriter(w)
+
+	for _, c := range comments {
+		if _, err := fmt.Fprintf(bw, "c %s\n", c); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintf(bw, "p cnf %d %d\n", s.NumVars(), s.NumClauses()); err != nil {
+		return err
+	}
+
+	for _, c := range s.original {
+		for _, l := range c {
+			if _, err := fmt.Fprintf(bw, "%d ", l); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(bw, "0"); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,548 @@
+// Package sat implements a small CDCL (conflict driven clause learning)
+// SAT solver: two watched literals propagation, first UIP learning,
+// VSIDS branching with phase saving, Luby restarts and learnt clauses
+// database reduction.
+//
+// The variables and the literals use the DIMACS convention: the variables
+// are numbered from 1 and a negative literal is the negation of a variable.
+package sat
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// lbool is a three-valued boolean.
+type lbool int8
+
+const (
+	lFalse lbool = -1
+	lUndef lbool = 0
+	lTrue  lbool = 1
+)
+
+// lit is the internal representation of a literal: 2*v for the variable v
+// and 2*v+1 for its negation (v is 0-based).
+type lit int32
+
+func mkLit(l int) lit {
+	if l > 0 {
+		return lit(2 * (l - 1))
+	}
+	return lit(2*(-l-1) + 1)
+}
+
+func (l lit) neg() lit {
+	return l ^ 1
+}
+
+func (l lit) v() int {
+	return int(l >> 1)
+}
+
+func (l lit) sign() bool {
+	return l&1 == 1
+}
+
+type clause struct {
+	lits     []lit
+	learnt   bool
+	deleted  bool
+	activity float64
+}
+
+// Solver is a CDCL SAT solver.
+// The clauses may be added between calls to Solve.
+type Solver struct {
+	// The problem clauses as they were added (DIMACS literals).
+	original [][]int
+
+	clauses []*clause
+	learnts []*clause
+
+	// The clauses watching the negation of each literal.
+	watches [][]*clause
+
+	assigns []lbool
+	level   []int
+	reason  []*clause
+	phase   []bool
+
+	trail    []lit
+	trailLim []int
+	qhead    int
+
+	activity []float64
+	varInc   float64
+	claInc   float64
+	order    *varHeap
+
+	seen []bool
+
+	// False if the clauses are known to be unsatisfiable.
+	ok bool
+
+	// The model found by the last successful Solve.
+	model []bool
+
+	// Statistics.
+	conflicts    int64
+	decisions    int64
+	propagations int64
+}
+
+// New creates an empty solver.
+func New() *Solver {
+	s := &Solver{
+		varInc: 1,
+		claInc: 1,
+		ok:     true,
+	}
+	s.order = &varHeap{activity: &s.activity}
+	return s
+}
+
+// NewVar adds a new variable and returns its (1-based) number.
+func (s *Solver) NewVar() int {
+	v := len(s.assigns)
+	s.watches = append(s.watches, nil, nil)
+	s.assigns = append(s.assigns, lUndef)
+	s.level = append(s.level, 0)
+	s.reason = append(s.reason, nil)
+	s.phase = append(s.phase, true)
+	s.activity = append(s.activity, 0)
+	s.seen = append(s.seen, false)
+	s.order.insert(v)
+	return v + 1
+}
+
+// NumVars returns the number of variables.
+func (s *Solver) NumVars() int {
+	return len(s.assigns)
+}
+
+// NumClauses returns the number of the problem clauses.
+func (s *Solver) NumClauses() int {
+	return len(s.original)
+}
+
+// Conflicts returns the number of conflicts met so far.
+func (s *Solver) Conflicts() int64 {
+	return s.conflicts
+}
+
+func (s *Solver) value(l lit) lbool {
+	a := s.assigns[l.v()]
+	if l.sign() {
+		return -a
+	}
+	return a
+}
+
+func (s *Solver) decisionLevel() int {
+	return len(s.trailLim)
+}
+
+// AddClause adds a clause made of DIMACS literals.
+// It returns false if the solver became trivially unsatisfiable.
+func (s *Solver) AddClause(lits ...int) bool {
+	s.original = append(s.original, append([]int(nil), lits...))
+	if !s.ok {
+		return false
+	}
+
+	s.cancelUntil(0)
+
+	var c []lit
+	for _, l := range lits {
+		if l == 0 || abs(l) > s.NumVars() {
+			panic(fmt.Sprintf("sat: invalid literal %d", l))
+		}
+		c = append(c, mkLit(l))
+	}
+	sort.Slice(c, func(i, j int) bool { return c[i] < c[j] })
+
+	// drop the duplicates and the false literals,
+	// skip the clause if it is satisfied or a tautology
+	j := 0
+	for i, l := range c {
+		if s.value(l) == lTrue || (i > 0 && l == c[i-1].neg()) {
+			return true
+		}
+		if s.value(l) == lFalse || (i > 0 && l == c[i-1]) {
+			continue
+		}
+		c[j] = l
+		j++
+	}
+	c = c[:j]
+
+	switch len(c) {
+	case 0:
+		s.ok = false
+	case 1:
+		s.enqueue(c[0], nil)
+		s.ok = s.propagate() == nil
+	default:
+		cl := &clause{lits: c}
+		s.clauses = append(s.clauses, cl)
+		s.attach(cl)
+	}
+
+	return s.ok
+}
+
+func (s *Solver) attach(c *clause) {
+	s.watches[c.lits[0].neg()] = append(s.watches[c.lits[0].neg()], c)
+	s.watches[c.lits[1].neg()] = append(s.watches[c.lits[1].neg()], c)
+}
+
+func (s *Solver) enqueue(l lit, from *clause) {
+	v := l.v()
+	if l.sign() {
+		s.assigns[v] = lFalse
+	} else {
+		s.assigns[v] = lTrue
+	}
+	s.level[v] = s.decisionLevel()
+	s.reason[v] = from
+	s.trail = append(s.trail, l)
+}
+
+// propagate runs the unit propagation and returns the conflicting
+// clause if any.
+func (s *Solver) propagate() *clause {
+	for s.qhead < len(s.trail) {
+		p := s.trail[s.qhead]
+		s.qhead++
+		s.propagations++
+
+		falseLit := p.neg()
+		ws := s.watches[p]
+		i, j := 0, 0
+		for i < len(ws) {
+			c := ws[i]
+			i++
+			if c.deleted {
+				continue
+			}
+
+			if c.lits[0] == falseLit {
+				c.lits[0], c.lits[1] = c.lits[1], c.lits[0]
+			}
+
+			if s.value(c.lits[0]) == lTrue {
+				ws[j] = c
+				j++
+				continue
+			}
+
+			moved := false
+			for k := 2; k < len(c.lits); k++ {
+				if s.value(c.lits[k]) != lFalse {
+					c.lits[1], c.lits[k] = c.lits[k], c.lits[1]
+					s.watches[c.lits[1].neg()] = append(s.watches[c.lits[1].neg()], c)
+					moved = true
+					break
+				}
+			}
+			if moved {
+				continue
+			}
+
+			ws[j] = c
+			j++
+			if s.value(c.lits[0]) == lFalse {
+				j += copy(ws[j:], ws[i:])
+				s.watches[p] = ws[:j]
+				s.qhead = len(s.trail)
+				return c
+			}
+			s.enqueue(c.lits[0], c)
+		}
+		s.watches[p] = ws[:j]
+	}
+	return nil
+}
+
+// analyze derives a first UIP clause from a conflict and returns it
+// with the level to backtrack to.
+func (s *Solver) analyze(confl *clause) ([]lit, int) {
+	learnt := []lit{0}
+	pathC := 0
+	p := lit(-1)
+	idx := len(s.trail) - 1
+
+	for {
+		if confl.learnt {
+			s.bumpClause(confl)
+		}
+
+		start := 0
+		if p >= 0 {
+			start = 1
+		}
+		for _, q := range confl.lits[start:] {
+			v := q.v()
+			if s.seen[v] || s.level[v] == 0 {
+				continue
+			}
+			s.bumpVar(v)
+			s.seen[v] = true
+			if s.level[v] >= s.decisionLevel() {
+				pathC++
+			} else {
+				learnt = append(learnt, q)
+			}
+		}
+
+		for !s.seen[s.trail[idx].v()] {
+			idx--
+		}
+		p = s.trail[idx]
+		idx--
+		confl = s.reason[p.v()]
+		s.seen[p.v()] = false
+		pathC--
+		if pathC == 0 {
+			break
+		}
+	}
+	learnt[0] = p.neg()
+
+	btLevel := 0
+	if len(learnt) > 1 {
+		max := 1
+		for i := 2; i < len(learnt); i++ {
+			if s.level[learnt[i].v()] > s.level[learnt[max].v()] {
+				max = i
+			}
+		}
+		learnt[1], learnt[max] = learnt[max], learnt[1]
+		btLevel = s.level[learnt[1].v()]
+	}
+
+	for _, l := range learnt {
+		s.seen[l.v()] = false
+	}
+
+	return learnt, btLevel
+}
+
+func (s *Solver) cancelUntil(level int) {
+	if s.decisionLevel() <= level {
+		return
+	}
+
+	for i := len(s.trail) - 1; i >= s.trailLim[level]; i-- {
+		v := s.trail[i].v()
+		s.phase[v] = !s.trail[i].sign()
+		s.assigns[v] = lUndef
+		s.reason[v] = nil
+		if !s.order.contains(v) {
+			s.order.insert(v)
+		}
+	}
+	s.trail = s.trail[:s.trailLim[level]]
+	s.trailLim = s.trailLim[:level]
+	s.qhead = len(s.trail)
+}
+
+func (s *Solver) bumpVar(v int) {
+	s.activity[v] += s.varInc
+	if s.activity[v] > 1e100 {
+		for i := range s.activity {
+			s.activity[i] *= 1e-100
+		}
+		s.varInc *= 1e-100
+	}
+	if s.order.contains(v) {
+		s.order.update(v)
+	}
+}
+
+func (s *Solver) bumpClause(c *clause) {
+	c.activity += s.claInc
+	if c.activity > 1e20 {
+		for _, l := range s.learnts {
+			l.activity *= 1e-20
+		}
+		s.claInc *= 1e-20
+	}
+}
+
+func (s *Solver) locked(c *clause) bool {
+	return s.reason[c.lits[0].v()] == c && s.value(c.lits[0]) == lTrue
+}
+
+// reduceDB removes half of the learnt clauses, the least active first.
+// The binary and the locked clauses are kept.
+func (s *Solver) reduceDB() {
+	sort.Slice(s.learnts, func(i, j int) bool {
+		return s.learnts[i].activity < s.learnts[j].activity
+	})
+
+	half := len(s.learnts) / 2
+	j := 0
+	for i, c := range s.learnts {
+		if i < half && len(c.lits) > 2 && !s.locked(c) {
+			c.deleted = true
+			continue
+		}
+		s.learnts[j] = c
+		j++
+	}
+	for i := j; i < len(s.learnts); i++ {
+		s.learnts[i] = nil
+	}
+	s.learnts = s.learnts[:j]
+}
+
+func (s *Solver) pickBranchLit() (lit, bool) {
+	for !s.order.empty() {
+		v := s.order.pop()
+		if s.assigns[v] == lUndef {
+			if s.phase[v] {
+				return lit(2 * v), true
+			}
+			return lit(2*v + 1), true
+		}
+	}
+	return 0, false
+}
+
+// checkInterval is the number of conflicts between two checks
+// of the solve context.
+const checkInterval = 256
+
+// search runs the CDCL loop until a model is found, the clauses are
+// proved unsatisfiable, maxConflicts conflicts are met or the context
+// is cancelled (lUndef).
+func (s *Solver) search(ctx context.Context, maxConflicts int64, maxLearnts *float64) lbool {
+	var conflicts int64
+	for {
+		confl := s.propagate()
+		if confl != nil {
+			s.conflicts++
+			conflicts++
+			if s.decisionLevel() == 0 {
+				return lFalse
+			}
+
+			if s.conflicts%checkInterval == 0 && ctx.Err() != nil {
+				s.cancelUntil(0)
+				return lUndef
+			}
+
+			learnt, btLevel := s.analyze(confl)
+			s.cancelUntil(btLevel)
+			if len(learnt) == 1 {
+				s.enqueue(learnt[0], nil)
+			} else {
+				c := &clause{lits: learnt, learnt: true}
+				s.learnts = append(s.learnts, c)
+				s.attach(c)
+				s.bumpClause(c)
+				s.enqueue(learnt[0], c)
+			}
+
+			s.varInc /= 0.95
+			s.claInc /= 0.999
+			continue
+		}
+
+		if conflicts >= maxConflicts {
+			s.cancelUntil(0)
+			return lUndef
+		}
+
+		if float64(len(s.learnts)-len(s.trail)) >= *maxLearnts {
+			s.reduceDB()
+			*maxLearnts *= 1.1
+		}
+
+		l, ok := s.pickBranchLit()
+		if !ok {
+			return lTrue
+		}
+		s.decisions++
+		s.trailLim = append(s.trailLim, len(s.trail))
+		s.enqueue(l, nil)
+	}
+}
+
+// Solve checks the satisfiability of the clauses added so far.
+// If they are satisfiable the model is available through Value.
+func (s *Solver) Solve() bool {
+	ok, _ := s.SolveContext(context.Background())
+	return ok
+}
+
+// SolveContext is like Solve but it gives up when the context
+// is cancelled, returning the context error.
+func (s *Solver) SolveContext(ctx context.Context) (bool, error) {
+	s.model = nil
+	if !s.ok {
+		return false, nil
+	}
+
+	if s.propagate() != nil {
+		s.ok = false
+		return false, nil
+	}
+
+	maxLearnts := float64(len(s.clauses))/3 + 1000
+	status := lUndef
+	for i := 0; status == lUndef; i++ {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		status = s.search(ctx, 100*luby(i), &maxLearnts)
+	}
+
+	if status == lTrue {
+		s.model = make([]bool, s.NumVars())
+		for v := range s.model {
+			s.model[v] = s.assigns[v] == lTrue
+		}
+	} else {
+		s.ok = false
+	}
+	s.cancelUntil(0)
+
+	return status == lTrue, nil
+}
+
+// Value returns the value of a variable in the last model found.
+func (s *Solver) Value(v int) bool {
+	if s.model == nil {
+		panic("sat: no model available")
+	}
+	return s.model[v-1]
+}
+
+// luby returns the i-th (0-based) element of the Luby sequence:
+// 1 1 2 1 1 2 4 1 1 2 ...
+func luby(i int) int64 {
+	size, seq := 1, 0
+	for size < i+1 {
+		seq++
+		size = 2*size + 1
+	}
+
+	for size-1 != i {
+		size = (size - 1) / 2
+		seq--
+		i = i % size
+	}
+
+	return int64(1) << uint(seq)
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
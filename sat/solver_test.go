@@ -0,0 +1,127 @@
+package sat
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func satisfied(s *Solver, clauses [][]int) bool {
+	for _, c := range clauses {
+		ok := false
+		for _, l := range c {
+			if (l > 0) == s.Value(abs(l)) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSolveSatisfiable(t *testing.T) {
+	s := New()
+	for i := 0; i < 3; i++ {
+		s.NewVar()
+	}
+
+	clauses := [][]int{{1, 2}, {-1, 3}, {-2, -3}, {-3, 1}}
+	for _, c := range clauses {
+		s.AddClause(c...)
+	}
+
+	assert.True(t, s.Solve())
+	assert.True(t, satisfied(s, clauses))
+}
+
+// TestSolvePigeonhole checks that 4 pigeons do not fit in 3 holes.
+func TestSolvePigeonhole(t *testing.T) {
+	const pigeons, holes = 4, 3
+
+	s := New()
+	v := func(p, h int) int { return p*holes + h + 1 }
+	for i := 0; i < pigeons*holes; i++ {
+		s.NewVar()
+	}
+
+	for p := 0; p < pigeons; p++ {
+		c := []int{}
+		for h := 0; h < holes; h++ {
+			c = append(c, v(p, h))
+		}
+		s.AddClause(c...)
+	}
+
+	for h := 0; h < holes; h++ {
+		for p := 0; p < pigeons; p++ {
+			for q := p + 1; q < pigeons; q++ {
+				s.AddClause(-v(p, h), -v(q, h))
+			}
+		}
+	}
+
+	assert.False(t, s.Solve())
+}
+
+func TestSolveRandom3SAT(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for n := 0; n < 50; n++ {
+		const vars = 40
+
+		s := New()
+		for i := 0; i < vars; i++ {
+			s.NewVar()
+		}
+
+		var clauses [][]int
+		for i := 0; i < 160; i++ {
+			c := make([]int, 3)
+			for j := range c {
+				c[j] = r.Intn(vars) + 1
+				if r.Intn(2) == 0 {
+					c[j] = -c[j]
+				}
+			}
+			clauses = append(clauses, c)
+			s.AddClause(c...)
+		}
+
+		if s.Solve() {
+			assert.True(t, satisfied(s, clauses))
+		}
+	}
+}
+
+func TestSolveIncremental(t *testing.T) {
+	s := New()
+	s.NewVar()
+	s.NewVar()
+
+	s.AddClause(1, 2)
+	assert.True(t, s.Solve())
+
+	s.AddClause(-1)
+	assert.True(t, s.Solve())
+	assert.True(t, s.Value(2))
+
+	s.AddClause(-2)
+	assert.False(t, s.Solve())
+}
+
+func TestWriteDIMACS(t *testing.T) {
+	s := New()
+	s.NewVar()
+	s.NewVar()
+	s.AddClause(1, -2)
+	s.AddClause(2)
+
+	var buf bytes.Buffer
+	assert.Nil(t, s.WriteDIMACS(&buf, "test"))
+	assert.Equal(t, "c test\np cnf 2 2\n1 -2 0\n2 0\n", buf.String())
+}
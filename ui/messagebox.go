@@ -18,19 +18,34 @@ const (
 
 	// Ok confirms the action
 	Ok int32 = 3
+
+	// Daily leaves the level for the daily puzzle
+	Daily int32 = 4
 )
 
 // LevelCompletedBox informs the user that the level gets completed.
 // The user may choose to repeat the current level or to move on
-// to the next level or to quit the game.
-func LevelCompletedBox(moves int32, window *sdl.Window) (int32, error) {
+// to the next level or to play the daily puzzle or to quit the game.
+// The par is shown if known (par > 0) and the streak is shown
+// if the level is the daily puzzle (streak > 0).
+func LevelCompletedBox(name string, par, moves, hints, streak int32, window *sdl.Window) (int32, error) {
 	buttons := []sdl.MessageBoxButtonData{
 		{Flags: sdl.MESSAGEBOX_BUTTON_RETURNKEY_DEFAULT, ButtonID: Continue, Text: "Continue"},
 		{Flags: 0, ButtonID: Repeat, Text: "Repeat"},
+		{Flags: 0, ButtonID: Daily, Text: "Daily puzzle"},
 		{Flags: sdl.MESSAGEBOX_BUTTON_ESCAPEKEY_DEFAULT, ButtonID: Quit, Text: "Quit"},
 	}
 
-	text := fmt.Sprintf("Completed the level in %d moves", int(moves))
+	text := fmt.Sprintf("Completed %s in %d moves", name, int(moves))
+	if par > 0 {
+		text += fmt.Sprintf(" (par %d)", int(par))
+	}
+	if hints > 0 {
+		text += fmt.Sprintf(" using %d hints", int(hints))
+	}
+	if streak > 0 {
+		text += fmt.Sprintf("\nDaily puzzle streak: %d days", int(streak))
+	}
 	mbdata := sdl.MessageBoxData{
 		Flags:       sdl.MESSAGEBOX_INFORMATION,
 		Window:      window,